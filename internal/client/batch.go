@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BatchError collects per-item errors of a batch request keyed by item,
+// e.g. a contract or pool address. Batch helpers return it alongside all
+// items that succeeded. BatchError is not safe for concurrent use.
+type BatchError[K comparable] struct {
+	Errors map[K]error
+}
+
+func NewBatchError[K comparable]() *BatchError[K] {
+	return &BatchError[K]{
+		Errors: make(map[K]error),
+	}
+}
+
+// Add records err for key. Nil errors are ignored.
+func (e *BatchError[K]) Add(key K, err error) {
+	if err == nil {
+		return
+	}
+	if e.Errors == nil {
+		e.Errors = make(map[K]error)
+	}
+	e.Errors[key] = err
+}
+
+// Len returns the number of failed items.
+func (e *BatchError[K]) Len() int {
+	return len(e.Errors)
+}
+
+// Failed returns the error recorded for key.
+func (e *BatchError[K]) Failed(key K) (error, bool) {
+	err, ok := e.Errors[key]
+	return err, ok
+}
+
+// ErrorOrNil returns nil when no item failed so callers can return the
+// batch error directly.
+func (e *BatchError[K]) ErrorOrNil() error {
+	if e == nil || len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *BatchError[K]) Error() string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(e.Errors)))
+	b.WriteString(" batch items failed")
+	for i, k := range e.sortedKeys() {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		fmt.Fprint(&b, k)
+		b.WriteString(": ")
+		b.WriteString(e.Errors[k].Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns all item errors ordered by key for use with errors.Is
+// and errors.As.
+func (e *BatchError[K]) Unwrap() []error {
+	list := make([]error, 0, len(e.Errors))
+	for _, k := range e.sortedKeys() {
+		list = append(list, e.Errors[k])
+	}
+	return list
+}
+
+// sortedKeys returns failed keys ordered by their string form.
+func (e *BatchError[K]) sortedKeys() []K {
+	keys := make([]K, 0, len(e.Errors))
+	names := make(map[K]string, len(e.Errors))
+	for k := range e.Errors {
+		keys = append(keys, k)
+		names[k] = fmt.Sprint(k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return names[keys[i]] < names[keys[j]] })
+	return keys
+}
+
+func IsBatchError[K comparable](err error) (*BatchError[K], bool) {
+	var e *BatchError[K]
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// Successes returns all items of a batch result whose key did not fail
+// according to err. Err may be nil, a *BatchError or an error wrapping
+// one. Any other error is considered fatal and yields nil.
+func Successes[K comparable, T any](items map[K]T, err error) map[K]T {
+	if err == nil {
+		return items
+	}
+	e, ok := IsBatchError[K](err)
+	if !ok {
+		return nil
+	}
+	res := make(map[K]T, len(items))
+	for k, v := range items {
+		if _, failed := e.Errors[k]; !failed {
+			res[k] = v
+		}
+	}
+	return res
+}
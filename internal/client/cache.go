@@ -0,0 +1,104 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"sync/atomic"
+	"time"
+
+	"blockwatch.cc/tzgo/tezos"
+)
+
+// CacheStats reports script cache efficiency.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	Size   int
+}
+
+type cacheStats struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// cacheEntry wraps cached values to track their age when a TTL is set.
+type cacheEntry struct {
+	val   any
+	added time.Time
+}
+
+func (c *Client) CacheGet(key tezos.Address) (any, bool) {
+	v, ok := c.cache.Get(key)
+	if ok {
+		if e, isEntry := v.(cacheEntry); isEntry {
+			if c.cacheTTL > 0 && time.Since(e.added) > c.cacheTTL {
+				c.cache.Remove(key)
+				ok = false
+			}
+			v = e.val
+		}
+	}
+	if ok {
+		c.stats.hits.Add(1)
+		return v, true
+	}
+	c.stats.misses.Add(1)
+	return nil, false
+}
+
+func (c *Client) CacheAdd(key tezos.Address, val any) {
+	if c.cacheTTL > 0 {
+		c.cache.Add(key, cacheEntry{val: val, added: time.Now()})
+		return
+	}
+	c.cache.Add(key, val)
+}
+
+// CacheLoad returns the cached value for key or calls load to fill the
+// cache. Concurrent loads for the same key are collapsed into a single
+// call. Errors are returned to all waiting callers but not cached.
+func (c *Client) CacheLoad(key tezos.Address, load func() (any, error)) (any, error) {
+	if v, ok := c.CacheGet(key); ok {
+		return v, nil
+	}
+	return c.flight.Do(key, func() (any, error) {
+		v, err := load()
+		if err == nil {
+			c.CacheAdd(key, v)
+		}
+		return v, err
+	})
+}
+
+// CacheRemove drops key from the cache so the next lookup refreshes it.
+func (c *Client) CacheRemove(key tezos.Address) {
+	c.cache.Remove(key)
+}
+
+// CacheStats returns cache hit and miss counters and the current size.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   c.stats.hits.Load(),
+		Misses: c.stats.misses.Load(),
+		Size:   c.cache.Len(),
+	}
+}
+
+// WithScriptCache replaces the script cache with a new cache of size sz
+// whose entries expire after ttl. A zero ttl keeps entries until evicted.
+func (c *Client) WithScriptCache(sz int, ttl time.Duration) *Client {
+	c.cacheTTL = ttl
+	return c.WithCacheSize(sz)
+}
+
+// WithKeepCode controls whether cached contract scripts retain their code
+// and views. By default code is stripped to save memory.
+func (c *Client) WithKeepCode(keep bool) *Client {
+	c.keepCode = keep
+	return c
+}
+
+func (c *Client) KeepCode() bool {
+	return c.keepCode
+}
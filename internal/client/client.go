@@ -12,10 +12,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"net/url"
+	"reflect"
 	"strings"
 	"time"
 
 	"blockwatch.cc/tzgo/tezos"
+	"blockwatch.cc/tzpro-go/internal/util"
 	"github.com/echa/log"
 	lru "github.com/hashicorp/golang-lru/v2"
 )
@@ -27,10 +30,22 @@ var (
 
 type Client struct {
 	transport  *http.Client
+	ownsTr     bool // transport was created by NewClient
 	log        log.Logger
 	base       Query
+	endpoints  *endpointList
+	network    *networkCheck
 	cache      *lru.TwoQueueCache[tezos.Address, any]
+	cacheTTL   time.Duration
+	keepCode   bool
+	stats      *cacheStats
+	metrics    *metrics
+	tape       *tape
+	rpc        *Client
+	ipfs       *Client
+	flight     *util.FlightGroup[tezos.Address, any]
 	headers    http.Header
+	defaults   url.Values
 	userAgent  string
 	numRetries int
 	retryDelay time.Duration
@@ -38,12 +53,14 @@ type Client struct {
 
 func NewClient(url string, httpClient *http.Client) *Client {
 	params, _ := ParseQuery(url)
+	ownTransport := httpClient == nil
 	if httpClient == nil {
 		httpClient = &http.Client{
 			Transport: &http.Transport{
 				Proxy:                 http.ProxyFromEnvironment,
 				ForceAttemptHTTP2:     true,
 				MaxIdleConns:          10,
+				MaxIdleConnsPerHost:   10,
 				MaxConnsPerHost:       10,
 				IdleConnTimeout:       30 * time.Second,
 				DisableCompression:    false,
@@ -60,9 +77,13 @@ func NewClient(url string, httpClient *http.Client) *Client {
 	cache, _ := lru.New2Q[tezos.Address, any](sz)
 	c := &Client{
 		transport:  httpClient,
+		ownsTr:     ownTransport,
 		log:        log.Disabled,
 		base:       params,
 		cache:      cache,
+		stats:      &cacheStats{},
+		metrics:    &metrics{},
+		flight:     &util.FlightGroup[tezos.Address, any]{},
 		headers:    make(http.Header),
 		userAgent:  "tzpro-go",
 		numRetries: 0,
@@ -80,11 +101,49 @@ func (c *Client) WithHeader(key, value string) *Client {
 	return c
 }
 
+// WithDefaultParams sets query arguments which are added to every request
+// unless the request already defines the same argument. Calling it again
+// replaces earlier defaults.
+func (c *Client) WithDefaultParams(params ...Query) *Client {
+	c.defaults = url.Values{}
+	for _, p := range params {
+		for k, v := range p.Query {
+			c.defaults[k] = append([]string(nil), v...)
+		}
+	}
+	return c
+}
+
+// withDefaults returns q with default query arguments merged in. Arguments
+// set on q take precedence. The caller's query values are not modified.
+func (c *Client) withDefaults(q Query) Query {
+	if len(c.defaults) == 0 {
+		return q
+	}
+	q = q.Clone()
+	for k, v := range c.defaults {
+		if _, ok := q.Query[k]; !ok {
+			q.Query[k] = append([]string(nil), v...)
+		}
+	}
+	return q
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+// including table and series queries. An empty string restores the
+// default.
 func (c *Client) WithUserAgent(s string) *Client {
+	if s == "" {
+		s = "tzpro-go"
+	}
 	c.userAgent = s
 	return c
 }
 
+func (c *Client) UserAgent() string {
+	return c.userAgent
+}
+
 func (c *Client) WithApiKey(s string) *Client {
 	if s != "" {
 		c.headers.Set("X-Api-Key", s)
@@ -97,15 +156,75 @@ func (c *Client) WithApiKey(s string) *Client {
 func (c *Client) WithUrl(url string) *Client {
 	if params, err := ParseQuery(url); err == nil {
 		c.base = params
+		c.endpoints = nil
+	}
+	return c
+}
+
+// WithRpc sets the client used for Tezos node RPC calls which the API
+// does not provide, e.g. view execution.
+func (c *Client) WithRpc(rpc *Client) *Client {
+	c.rpc = rpc
+	return c
+}
+
+// Rpc returns the node RPC client or nil when none is configured.
+func (c *Client) Rpc() *Client {
+	return c.rpc
+}
+
+// WithIpfs sets the client used to load off-chain data from IPFS, e.g.
+// TZIP-21 token metadata.
+func (c *Client) WithIpfs(ipfs *Client) *Client {
+	c.ipfs = ipfs
+	return c
+}
+
+// Ipfs returns the IPFS client or nil when none is configured.
+func (c *Client) Ipfs() *Client {
+	return c.ipfs
+}
+
+// WithEndpoints configures alternative API servers. Requests are sent to
+// the active endpoint and fail over to the next healthy endpoint on
+// network errors and 5xx responses. The first url becomes the base url.
+func (c *Client) WithEndpoints(urls ...string) *Client {
+	if len(urls) == 0 {
+		c.endpoints = nil
+		return c
+	}
+	c.WithUrl(urls[0])
+	if l := newEndpointList(urls); l.Len() > 1 {
+		c.endpoints = l
+	} else {
+		c.endpoints = nil
 	}
 	return c
 }
 
+// Endpoint returns the server url requests are currently sent to.
+func (c *Client) Endpoint() string {
+	if c.endpoints != nil {
+		return c.endpoints.current().String()
+	}
+	return c.base.Server
+}
+
 func (c *Client) WithTLS(tc *tls.Config) *Client {
 	c.transport.Transport.(*http.Transport).TLSClientConfig = tc
 	return c
 }
 
+// WithCompression controls whether the default transport requests gzip
+// compressed responses. Compressed responses are transparently decoded.
+// Has no effect on custom transports that are not *http.Transport.
+func (c *Client) WithCompression(enable bool) *Client {
+	if tr, ok := c.transport.Transport.(*http.Transport); ok {
+		tr.DisableCompression = !enable
+	}
+	return c
+}
+
 func (c *Client) WithTimeout(d time.Duration) *Client {
 	if tr, ok := c.transport.Transport.(*http.Transport); ok {
 		tr.ResponseHeaderTimeout = d
@@ -114,6 +233,47 @@ func (c *Client) WithTimeout(d time.Duration) *Client {
 	return c
 }
 
+// defaultTransport returns the transport created by NewClient or nil
+// when the caller supplied its own http client.
+func (c *Client) defaultTransport() *http.Transport {
+	if !c.ownsTr {
+		return nil
+	}
+	tr, _ := c.transport.Transport.(*http.Transport)
+	return tr
+}
+
+// WithMaxConnsPerHost limits concurrent connections to the API server
+// (default 10). Raise it together with WithMaxIdleConns for scanners that
+// send many small requests in parallel. Zero means no limit. Has no effect
+// when a custom http client was passed to NewClient.
+func (c *Client) WithMaxConnsPerHost(n int) *Client {
+	if tr := c.defaultTransport(); tr != nil {
+		tr.MaxConnsPerHost = n
+	}
+	return c
+}
+
+// WithMaxIdleConns sets the number of idle connections kept open for reuse
+// (default 10). Keeping it at or above the number of concurrent requests
+// avoids reconnects. Has no effect on custom http clients.
+func (c *Client) WithMaxIdleConns(n int) *Client {
+	if tr := c.defaultTransport(); tr != nil {
+		tr.MaxIdleConns = n
+		tr.MaxIdleConnsPerHost = n
+	}
+	return c
+}
+
+// WithIdleConnTimeout sets how long idle connections are kept open
+// (default 30s). Has no effect on custom http clients.
+func (c *Client) WithIdleConnTimeout(d time.Duration) *Client {
+	if tr := c.defaultTransport(); tr != nil {
+		tr.IdleConnTimeout = d
+	}
+	return c
+}
+
 func (c *Client) WithRetry(num int, delay time.Duration) *Client {
 	c.numRetries = num
 	if num < 0 {
@@ -128,6 +288,10 @@ func (c *Client) WithLogger(log log.Logger) *Client {
 	return c
 }
 
+func (c *Client) Logger() log.Logger {
+	return c.log
+}
+
 func (c *Client) WithCacheSize(sz int) *Client {
 	if sz < 2 {
 		sz = 2
@@ -149,42 +313,101 @@ func (c Client) RetryDelay() time.Duration {
 	return c.retryDelay
 }
 
-func (c *Client) CacheGet(key tezos.Address) (any, bool) {
-	return c.cache.Get(key)
-}
-
-func (c *Client) CacheAdd(key tezos.Address, val any) {
-	c.cache.Add(key, val)
+func (c *Client) Get(ctx context.Context, path string, headers http.Header, result any) error {
+	return c.call(ctx, http.MethodGet, pathQuery(path), headers, nil, result)
 }
 
-func (c *Client) Get(ctx context.Context, path string, headers http.Header, result any) error {
-	return c.call(ctx, http.MethodGet, path, headers, nil, result)
+// GetQuery sends a GET request for q. Requests fail with the first
+// builder error recorded in q before anything is sent.
+func (c *Client) GetQuery(ctx context.Context, q Query, headers http.Header, result any) error {
+	return c.call(ctx, http.MethodGet, q, headers, nil, result)
 }
 
 func (c *Client) Post(ctx context.Context, path string, headers http.Header, data, result any) error {
-	return c.call(ctx, http.MethodPost, path, headers, data, result)
+	return c.call(ctx, http.MethodPost, pathQuery(path), headers, data, result)
 }
 
 func (c *Client) Put(ctx context.Context, path string, headers http.Header, data, result any) error {
-	return c.call(ctx, http.MethodPut, path, headers, data, result)
+	return c.call(ctx, http.MethodPut, pathQuery(path), headers, data, result)
 }
 
 func (c *Client) Delete(ctx context.Context, path string, headers http.Header) error {
-	return c.call(ctx, http.MethodDelete, path, headers, nil, nil)
+	return c.call(ctx, http.MethodDelete, pathQuery(path), headers, nil, nil)
+}
+
+// Do sends a raw request to path relative to the configured base url
+// using default headers, retries and response decoding. A non-nil body
+// is read into memory before sending so it can be resent on retries and
+// endpoint failover. Use for API endpoints the SDK does not wrap yet.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body io.Reader, result any) error {
+	q := c.base.Clone().WithPath(path)
+	for n, v := range query {
+		q.Query[n] = append([]string(nil), v...)
+	}
+	var data any
+	if body != nil {
+		data = body
+	}
+	return c.call(ctx, method, q, nil, data, result)
 }
 
 func (c *Client) Async(ctx context.Context, path string, headers http.Header, result any) FutureResult {
-	return c.callAsync(ctx, http.MethodGet, path, headers, nil, result)
+	return c.callAsync(ctx, http.MethodGet, pathQuery(path), headers, nil, result)
+}
+
+func (c *Client) call(ctx context.Context, method string, q Query, headers http.Header, data, result any) error {
+	return c.callAsync(ctx, method, q, headers, data, result).Receive(ctx)
+}
+
+func (c *Client) callAsync(ctx context.Context, method string, q Query, headers http.Header, data, result any) FutureResult {
+	// fail requests built from queries with builder errors
+	if err := q.Err(); err != nil {
+		return newFutureError(err)
+	}
+	if err := c.checkNetwork(ctx); err != nil {
+		return newFutureError(err)
+	}
+	return c.send(ctx, method, q, headers, data, result)
 }
 
-func (c *Client) call(ctx context.Context, method, path string, headers http.Header, data, result any) error {
-	return c.callAsync(ctx, method, path, headers, data, result).Receive(ctx)
+// pathQuery converts a relative path or absolute url including optional
+// query arguments into a Query. Relative paths are sent to the base url.
+func pathQuery(path string) Query {
+	q := NewQuery()
+	u, err := url.Parse(path)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	if u.IsAbs() {
+		q.Server = u.Scheme + "://" + u.Host
+	}
+	q.Path = u.EscapedPath()
+	q.Query = u.Query()
+	return q
 }
 
-func (c *Client) callAsync(ctx context.Context, method, path string, headers http.Header, data, result any) FutureResult {
-	if !strings.HasPrefix(path, "http") {
-		path = c.base.WithPath(path).Url()
+func (c *Client) send(ctx context.Context, method string, q Query, headers http.Header, data, result any) FutureResult {
+	if q.Server == "" {
+		q.Server = c.base.Server
+	}
+	q = c.withDefaults(q)
+
+	// translate format selection into an Accept header
+	q, format := splitFormat(q)
+	if format != "" {
+		if !format.IsValid() {
+			return newFutureError(fmt.Errorf("unsupported format '%s'", format))
+		}
+		if _, ok := result.(io.Writer); !ok && result != nil && format == FormatCSV {
+			return newFutureError(fmt.Errorf("format %s requires an io.Writer result", format))
+		}
+		if headers == nil {
+			headers = make(http.Header)
+		}
+		headers.Set("Accept", format.MimeType())
 	}
+	path := q.Url()
 
 	req, err := c.newRequest(ctx, method, path, headers, data, result)
 	if err != nil {
@@ -208,6 +431,9 @@ func (c *Client) newRequest(ctx context.Context, method, path string, headers ht
 		headers = make(http.Header)
 	}
 	headers.Set("User-Agent", c.userAgent)
+	if id, ok := RequestId(ctx); ok {
+		headers.Set(RequestIdHeader, id)
+	}
 
 	// copy default headers
 	for n, v := range c.headers {
@@ -219,11 +445,20 @@ func (c *Client) newRequest(ctx context.Context, method, path string, headers ht
 	// prepare POST/PUT/PATCH payload
 	var body io.Reader
 	if data != nil {
-		b, err := json.Marshal(data)
-		if err != nil {
-			return nil, err
+		if r, ok := data.(io.Reader); ok {
+			// buffer streams so the request can be replayed via GetBody
+			b, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(b)
+		} else {
+			b, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewBuffer(b)
 		}
-		body = bytes.NewBuffer(b)
 		if headers.Get("Content-Type") == "" {
 			headers.Set("Content-Type", "application/json")
 		}
@@ -272,12 +507,26 @@ func (c *Client) handleRequest(req *request) {
 		return string(r)
 	}))
 
+	c.metrics.requests.Add(1)
 	var (
 		resp *http.Response
 		err  error
 	)
 	for retries := c.numRetries + 1; retries > 0; retries-- {
-		resp, err = c.transport.Do(req.httpRequest)
+		if retries <= c.numRetries {
+			c.metrics.retries.Add(1)
+			// the previous attempt consumed the body
+			if req.httpRequest.GetBody != nil {
+				body, err := req.httpRequest.GetBody()
+				if err != nil {
+					c.metrics.errors.Add(1)
+					req.responseChan <- &response{err: err, request: req.String()}
+					return
+				}
+				req.httpRequest.Body = body
+			}
+		}
+		resp, err = c.do(req)
 		if err == nil {
 			break
 		}
@@ -286,6 +535,7 @@ func (c *Client) handleRequest(req *request) {
 		}
 		select {
 		case <-req.httpRequest.Context().Done():
+			c.metrics.errors.Add(1)
 			req.responseChan <- &response{
 				err:     req.httpRequest.Context().Err(),
 				request: req.String(),
@@ -296,6 +546,7 @@ func (c *Client) handleRequest(req *request) {
 		}
 	}
 	if err != nil {
+		c.metrics.errors.Add(1)
 		req.responseChan <- &response{err: err, request: req.String()}
 		return
 	}
@@ -311,7 +562,11 @@ func (c *Client) handleRequest(req *request) {
 		if stream, ok := req.responseVal.(io.Writer); ok {
 			// c.log.Tracef("start streaming response")
 			// forward stream
-			_, err := io.Copy(stream, resp.Body)
+			n, err := io.Copy(stream, resp.Body)
+			c.metrics.bytes.Add(n)
+			if err != nil {
+				c.metrics.errors.Add(1)
+			}
 			// close consumer if possible
 			if closer, ok := req.responseVal.(io.WriteCloser); ok {
 				// c.log.Tracef("closing stream after %d bytes", n)
@@ -333,7 +588,9 @@ func (c *Client) handleRequest(req *request) {
 
 	// Read the raw bytes
 	respBytes, err := io.ReadAll(resp.Body)
+	c.metrics.bytes.Add(int64(len(respBytes)))
 	if err != nil {
+		c.metrics.errors.Add(1)
 		req.responseChan <- &response{
 			status:  resp.StatusCode,
 			request: req.String(),
@@ -347,6 +604,7 @@ func (c *Client) handleRequest(req *request) {
 	// error codes as details which we cannot parse here; some other APIs
 	// even send 5xx error codes to signal non-error situations)
 	if resp.StatusCode >= 400 {
+		c.metrics.errors.Add(1)
 		if resp.StatusCode == 429 {
 			// TODO: read rate limit header
 			wait := 5 * time.Second
@@ -364,14 +622,24 @@ func (c *Client) handleRequest(req *request) {
 		return
 	}
 
-	// unmarshal any JSON response
-	isJson := strings.Contains(resp.Header.Get("Content-Type"), "application/json")
-
-	// do this even if the response looks like JSON
-	isJson = isJson || bytes.HasPrefix(respBytes, []byte("{")) || bytes.HasPrefix(respBytes, []byte("["))
+	// empty or null replies to single object requests
+	if resp.StatusCode == http.StatusOK && req.responseVal != nil && isEmptyBody(respBytes) {
+		if !isListResult(req.responseVal) {
+			err = ErrNoData
+		}
+		req.responseChan <- &response{
+			status:  resp.StatusCode,
+			request: req.String(),
+			headers: mergeHeaders(req.responseHeaders, resp.Header, resp.Trailer),
+			err:     err,
+		}
+		return
+	}
 
-	if isJson && req.responseVal != nil && (resp.ContentLength > 0 || resp.ContentLength == -1) {
-		if err = json.Unmarshal(respBytes, req.responseVal); err == nil {
+	// unmarshal JSON and msgpack responses
+	decode := replyDecoder(resp.Header.Get("Content-Type"), respBytes)
+	if decode != nil && req.responseVal != nil && (resp.ContentLength > 0 || resp.ContentLength == -1) {
+		if err = decode(respBytes, req.responseVal); err == nil {
 			req.responseChan <- &response{
 				status:  resp.StatusCode,
 				request: req.String(),
@@ -381,6 +649,7 @@ func (c *Client) handleRequest(req *request) {
 			return
 		}
 		err = fmt.Errorf("unmarshaling reply: %w", err)
+		c.metrics.errors.Add(1)
 	}
 	req.responseChan <- &response{
 		status:  resp.StatusCode,
@@ -390,3 +659,61 @@ func (c *Client) handleRequest(req *request) {
 		err:     err,
 	}
 }
+
+// replyDecoder selects the decoder for a reply by content type. Replies
+// which look like JSON are decoded as JSON regardless of content type.
+// It returns nil for other formats.
+func replyDecoder(contentType string, buf []byte) func([]byte, any) error {
+	switch {
+	case strings.Contains(contentType, FormatMsgpack.MimeType()),
+		strings.Contains(contentType, "application/x-msgpack"):
+		return decodeMsgpack
+	case strings.Contains(contentType, FormatJSON.MimeType()),
+		bytes.HasPrefix(buf, []byte("{")),
+		bytes.HasPrefix(buf, []byte("[")):
+		return json.Unmarshal
+	default:
+		return nil
+	}
+}
+
+func isEmptyBody(buf []byte) bool {
+	buf = bytes.TrimSpace(buf)
+	return len(buf) == 0 || bytes.Equal(buf, []byte("null"))
+}
+
+// listResult is implemented by non-slice result types which decode
+// list replies and accept empty lists.
+type listResult interface {
+	listResult()
+}
+
+// isListResult reports whether v points to a slice, map or list result
+// which may legitimately be empty.
+func isListResult(v any) bool {
+	if _, ok := v.(listResult); ok {
+		return true
+	}
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Map)
+}
+
+// formatArg is the query argument set by Query.WithFormat. It is removed
+// before sending and replaced by an Accept header.
+const formatArg = "format"
+
+// splitFormat removes the format argument from q and returns it. Only an
+// exact format key matches, other keys ending in format stay untouched.
+// The caller's query values are not modified.
+func splitFormat(q Query) (Query, FormatType) {
+	if !q.Query.Has(formatArg) {
+		return q, ""
+	}
+	f := q.Query.Get(formatArg)
+	q = q.Clone()
+	q.Query.Del(formatArg)
+	return q, FormatType(f)
+}
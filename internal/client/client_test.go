@@ -0,0 +1,124 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"blockwatch.cc/tzgo/tezos"
+)
+
+const doTestBody = `{"hello":"world"}`
+
+// streamReader hides the concrete reader type so the body cannot be
+// replayed by net/http on its own.
+type streamReader struct{ io.Reader }
+
+func newEchoServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(buf)
+	}))
+}
+
+// flakyTransport drops the first request after consuming its body.
+type flakyTransport struct {
+	calls atomic.Int32
+}
+
+func (f *flakyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if f.calls.Add(1) == 1 {
+		io.Copy(io.Discard, r.Body)
+		r.Body.Close()
+		return nil, errors.New("connection reset")
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestDoResendsBodyOnRetry(t *testing.T) {
+	srv := newEchoServer(t)
+	defer srv.Close()
+
+	tr := &flakyTransport{}
+	c := NewClient(srv.URL, &http.Client{Transport: tr}).WithRetry(1, 0)
+	var res map[string]string
+	body := streamReader{strings.NewReader(doTestBody)}
+	if err := c.Do(context.Background(), http.MethodPost, "/echo", nil, body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if n := tr.calls.Load(); n != 2 {
+		t.Fatalf("got %d attempts, want 2", n)
+	}
+	if res["hello"] != "world" {
+		t.Fatalf("retry sent wrong body, got %v", res)
+	}
+}
+
+func TestDoResendsBodyOnFailover(t *testing.T) {
+	bad := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer bad.Close()
+	good := newEchoServer(t)
+	defer good.Close()
+
+	c := NewClient(bad.URL, nil).WithEndpoints(bad.URL, good.URL)
+	var res map[string]string
+	body := streamReader{strings.NewReader(doTestBody)}
+	if err := c.Do(context.Background(), http.MethodPost, "/echo", nil, body, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res["hello"] != "world" {
+		t.Fatalf("failover sent wrong body, got %v", res)
+	}
+}
+
+func TestGetQueryFailsOnBuilderError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, nil)
+	q := NewQuery().WithSender(tezos.Address{}).WithReceiver(tezos.Address{}).WithPath("/v1/test")
+	var res []any
+	err := c.GetQuery(context.Background(), q, nil, &res)
+	if !errors.Is(err, ErrInvalidAddress) || !strings.HasPrefix(err.Error(), "sender:") {
+		t.Fatalf("got error %v, want first builder error", err)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Fatalf("sent %d requests for an invalid query", n)
+	}
+	if u := q.Url(); u != "/v1/test" {
+		t.Fatalf("unexpected url %q", u)
+	}
+}
+
+func TestWithLimitAboveMax(t *testing.T) {
+	q := NewQuery().WithLimit(MaxLimit)
+	if err := q.Err(); err != nil {
+		t.Fatalf("unexpected error at max limit: %v", err)
+	}
+	q = NewQuery().WithLimit(MaxLimit + 1)
+	if q.Err() == nil {
+		t.Fatal("expected error above max limit")
+	}
+	if q.Query.Has("limit") {
+		t.Errorf("limit set despite error: %v", q.Query)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"context"
+)
+
+// RequestIdHeader is the HTTP header used to propagate request ids.
+const RequestIdHeader = "X-Request-Id"
+
+type requestIdKey struct{}
+
+// WithRequestId returns a copy of ctx carrying request id. Requests
+// executed with this context send the id in the X-Request-Id header.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+// RequestId returns the request id stored in ctx, if any.
+func RequestId(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIdKey{}).(string)
+	return id, ok && id != ""
+}
@@ -7,20 +7,43 @@ import (
 	"encoding"
 	"encoding/hex"
 	"fmt"
-	"hash/fnv"
 
 	"bytes"
 	"encoding/json"
 	"reflect"
+	"strings"
 	"sync"
 
 	"blockwatch.cc/tzpro-go/internal/util"
 )
 
 type Decoder struct {
-	id    uint32
+	typ   string
 	idx   []int // we only handle flat structs because thats what the SDK uses
 	flags []int
+	cols  []string
+	names []string
+}
+
+// DecodeError reports the table column and struct field which failed
+// to decode.
+type DecodeError struct {
+	Row    int    // row index in result, -1 for single value decoding
+	Column string // column name in response
+	Field  string // Go struct field name
+	Type   string // Go struct type name
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Row >= 0 {
+		return fmt.Sprintf("decode: row %d column %q into %s.%s: %v", e.Row, e.Column, e.Type, e.Field, e.Err)
+	}
+	return fmt.Sprintf("decode: column %q into %s.%s: %v", e.Column, e.Type, e.Field, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
 }
 
 func DecodeSlice(buf []byte, fields []string, val any) error {
@@ -41,13 +64,21 @@ func DecodeSlice(buf []byte, fields []string, val any) error {
 	}
 
 	jdec := json.NewDecoder(bytes.NewReader(buf))
-	_, err = jdec.Token()
+	tok, err := jdec.Token()
 	if err != nil {
 		return err
 	}
+	switch tok {
+	case nil:
+		// null result
+		return nil
+	case json.Delim('['):
+	default:
+		return fmt.Errorf("decode: expected JSON array, got %v", tok)
+	}
 
 	// walk outer json array [
-	for jdec.More() {
+	for row := 0; jdec.More(); row++ {
 		elem := reflect.New(etyp)
 		ev := elem
 		if elem.Elem().Kind() == reflect.Ptr {
@@ -56,7 +87,11 @@ func DecodeSlice(buf []byte, fields []string, val any) error {
 		}
 		err = dec.decode(jdec, ev)
 		if err != nil {
-			return err
+			if e, ok := err.(*DecodeError); ok {
+				e.Row = row
+				return e
+			}
+			return fmt.Errorf("row %d: %w", row, err)
 		}
 		v.Set(reflect.Append(v, elem.Elem()))
 	}
@@ -87,75 +122,123 @@ func Decode(buf []byte, fields []string, val any) error {
 
 func (d *Decoder) decode(dec *json.Decoder, dst reflect.Value) error {
 	// read open bracket
-	_, err := dec.Token()
+	tok, err := dec.Token()
 	if err != nil {
 		return err
 	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("decode: expected row array, got %v", tok)
+	}
 
 	// allocate and deref ptr types
 	dst = derefValue(dst)
+	if dst.Kind() != reflect.Struct {
+		return fmt.Errorf("decode: non-struct row type %s", dst.Type())
+	}
 
 	// while the array contains values
 	for i, pos := range d.idx {
-		// custom pre-decoding
-		f := derefValue(dst.Field(pos))
-		switch {
-		case d.flags[i]&fieldFlagHex > 0:
-			// hex: decode hex to bin, then call binary unmarshaler
-			var s string
-			if err := dec.Decode(&s); err != nil {
+		if !dec.More() {
+			return fmt.Errorf("decode: row has %d columns, expected %d", i, len(d.idx))
+		}
+		// skip ignored columns
+		if pos < 0 {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
 				return err
 			}
-			if len(s) > 0 {
-				buf, err := hex.DecodeString(s)
-				if err != nil {
-					return err
-				}
-				if err := f.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(buf); err != nil {
-					return err
-				}
+			continue
+		}
+		// custom pre-decoding
+		f := derefValue(dst.Field(pos))
+		if err := d.decodeField(dec, f, d.flags[i]); err != nil {
+			return &DecodeError{
+				Row:    -1,
+				Column: d.cols[i],
+				Field:  d.names[i],
+				Type:   d.typ,
+				Err:    err,
 			}
-		case d.flags[i]&fieldFlagTime > 0:
-			// time: decode int or time string
-			var tm util.Time
-			if err := dec.Decode(&tm); err != nil {
+		}
+	}
+
+	// read closing bracket
+	if dec.More() {
+		return fmt.Errorf("decode: row has more than %d columns", len(d.idx))
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func (d *Decoder) decodeField(dec *json.Decoder, f reflect.Value, flags int) error {
+	switch {
+	case flags&fieldFlagHex > 0:
+		// hex: decode hex to bin, then call binary unmarshaler
+		var s string
+		if err := dec.Decode(&s); err != nil {
+			return err
+		}
+		s = strings.TrimPrefix(s, "0x")
+		if len(s) > 0 {
+			buf, err := hex.DecodeString(s)
+			if err != nil {
 				return err
 			}
-			f.Set(reflect.ValueOf(tm.Time()))
-		case d.flags[i]&fieldFlagBool > 0:
-			// bool: decode int or string
-			var b util.Bool
-			if err := dec.Decode(&b); err != nil {
-				return err
+			u, ok := f.Addr().Interface().(encoding.BinaryUnmarshaler)
+			if !ok {
+				return fmt.Errorf("hex column into non-binary type %s", f.Type())
 			}
-			f.Set(reflect.ValueOf(b.Bool()))
-		default:
-			// decode an array value
-			if err := dec.Decode(f.Addr().Interface()); err != nil {
+			if err := u.UnmarshalBinary(buf); err != nil {
 				return err
 			}
 		}
+	case flags&fieldFlagTime > 0:
+		// time: decode int or time string, null keeps the zero time
+		var tm *util.Time
+		if err := dec.Decode(&tm); err != nil {
+			return err
+		}
+		if tm == nil {
+			return nil
+		}
+		v := reflect.ValueOf(tm.Time())
+		if !v.Type().AssignableTo(f.Type()) {
+			return fmt.Errorf("time column into type %s", f.Type())
+		}
+		f.Set(v)
+	case flags&fieldFlagBool > 0:
+		// bool: decode int or string, null keeps false
+		var b *util.Bool
+		if err := dec.Decode(&b); err != nil {
+			return err
+		}
+		if f.Kind() != reflect.Bool {
+			return fmt.Errorf("bool column into type %s", f.Type())
+		}
+		if b != nil {
+			f.SetBool(b.Bool())
+		}
+	default:
+		// decode an array value
+		if err := dec.Decode(f.Addr().Interface()); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	// read closing bracket
-	_, err = dec.Token()
-	return err
+// decoderKey identifies a decoder by Go type and column list. Using the
+// reflect type avoids collisions between unnamed types like pointers.
+type decoderKey struct {
+	typ  reflect.Type
+	cols string
 }
 
-var decoderMap = make(map[uint32]*Decoder)
+var decoderMap = make(map[decoderKey]*Decoder)
 var decoderLock sync.RWMutex
 
-func typeHash(n string, s ...string) uint32 {
-	h := fnv.New32a()
-	h.Write([]byte(n))
-	for _, v := range s {
-		h.Write([]byte(v))
-	}
-	return h.Sum32()
-}
-
 func buildDecoder(typ reflect.Type, fields []string) (*Decoder, error) {
-	key := typeHash(typ.Name(), fields...)
+	key := decoderKey{typ: typ, cols: strings.Join(fields, "\x00")}
 	decoderLock.RLock()
 	d, ok := decoderMap[key]
 	decoderLock.RUnlock()
@@ -169,10 +252,16 @@ func buildDecoder(typ reflect.Type, fields []string) (*Decoder, error) {
 	if len(fields) == 0 {
 		fields = tinfo.Aliases()
 	}
+	name := typ.Name()
+	if typ.Kind() == reflect.Ptr {
+		name = typ.Elem().Name()
+	}
 	d = &Decoder{
-		id:    key,
+		typ:   name,
 		idx:   make([]int, len(fields)),
 		flags: make([]int, len(fields)),
+		cols:  append([]string{}, fields...),
+		names: make([]string, len(fields)),
 	}
 
 	for i, f := range fields {
@@ -182,10 +271,12 @@ func buildDecoder(typ reflect.Type, fields []string) (*Decoder, error) {
 		}
 		// skip ignore fields
 		if fi.ContainsFlag(fieldFlagIgnore) {
+			d.idx[i] = -1
 			continue
 		}
 		d.idx[i] = fi.Idx[0] // first index only, no nested structs
 		d.flags[i] = fi.Flags
+		d.names[i] = fi.Name
 	}
 	decoderLock.Lock()
 	decoderMap[key] = d
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+type fuzzRow struct {
+	RowId  uint64    `json:"row_id"`
+	Name   string    `json:"name"`
+	Time   time.Time `json:"time"`
+	Active bool      `json:"active"`
+	Amount *float64  `json:"amount"`
+}
+
+var decodeSliceCases = []struct {
+	name string
+	data string
+	ok   bool
+}{
+	{"empty", `[]`, true},
+	{"null", `null`, true},
+	{"full", `[[1,"a","2024-01-02T03:04:05Z",true,1.5],[2,"b",1704164645000,0,null]]`, true},
+	{"null_columns", `[[null,null,null,null,null]]`, true},
+	{"short_row", `[[1,"a"]]`, false},
+	{"empty_row", `[[]]`, false},
+	{"long_row", `[[1,"a","2024-01-02T03:04:05Z",true,1.5,"extra"]]`, false},
+	{"object_row", `[{"row_id":1}]`, false},
+	{"scalar_row", `[1]`, false},
+	{"string_row", `["a"]`, false},
+	{"object", `{"rows":[]}`, false},
+	{"type_mismatch", `[["x","a","2024-01-02T03:04:05Z",true,1.5]]`, false},
+	{"truncated", `[[1,"a","2024-01-02T03:04:05Z",tr`, false},
+}
+
+func TestDecodeSlice(t *testing.T) {
+	cols := []string{"row_id", "name", "time", "active", "amount"}
+	for _, c := range decodeSliceCases {
+		t.Run(c.name, func(t *testing.T) {
+			var rows []fuzzRow
+			err := DecodeSlice([]byte(c.data), cols, &rows)
+			if c.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !c.ok && err == nil {
+				t.Fatalf("expected error, got %d rows", len(rows))
+			}
+		})
+	}
+}
+
+func FuzzDecodeSlice(f *testing.F) {
+	for _, c := range decodeSliceCases {
+		f.Add([]byte(c.data))
+	}
+	cols := []string{"row_id", "name", "time", "active", "amount"}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// must never panic, neither for value nor pointer rows
+		var rows []fuzzRow
+		if err := DecodeSlice(data, cols, &rows); err == nil {
+			for i, r := range rows {
+				if r.Amount != nil && *r.Amount != *r.Amount {
+					t.Fatalf("row %d: NaN amount", i)
+				}
+			}
+		}
+		var prows []*fuzzRow
+		if err := DecodeSlice(data, cols, &prows); err == nil {
+			for i, r := range prows {
+				if r == nil {
+					t.Fatalf("row %d: nil row without error", i)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// EndpointRetryAfter is the time a failed endpoint is skipped before
+// it is considered healthy again.
+var EndpointRetryAfter = 30 * time.Second
+
+type endpoint struct {
+	url      *url.URL
+	failures int
+	lastFail time.Time
+}
+
+func (e *endpoint) isHealthy(now time.Time) bool {
+	return e.failures == 0 || now.Sub(e.lastFail) > EndpointRetryAfter
+}
+
+func (e *endpoint) matches(u *url.URL) bool {
+	return e.url.Scheme == u.Scheme && e.url.Host == u.Host
+}
+
+// endpointList tracks health of alternative API servers and the
+// currently active server.
+type endpointList struct {
+	sync.Mutex
+	list   []*endpoint
+	active int
+}
+
+func newEndpointList(urls []string) *endpointList {
+	l := &endpointList{}
+	for _, v := range urls {
+		q, err := ParseQuery(v)
+		if err != nil {
+			continue
+		}
+		u, err := url.Parse(q.Server)
+		if err != nil {
+			continue
+		}
+		l.list = append(l.list, &endpoint{url: u})
+	}
+	return l
+}
+
+func (l *endpointList) Len() int {
+	return len(l.list)
+}
+
+func (l *endpointList) current() *url.URL {
+	l.Lock()
+	defer l.Unlock()
+	return l.list[l.active].url
+}
+
+func (l *endpointList) find(u *url.URL) int {
+	for i, e := range l.list {
+		if e.matches(u) {
+			return i
+		}
+	}
+	return -1
+}
+
+// success resets failure state of the endpoint serving u.
+func (l *endpointList) success(u *url.URL) {
+	l.Lock()
+	defer l.Unlock()
+	if i := l.find(u); i >= 0 {
+		l.list[i].failures = 0
+	}
+}
+
+// fail marks the endpoint serving u as failed and activates the next
+// healthy endpoint. Returns false when no other endpoint is available.
+func (l *endpointList) fail(u *url.URL) (*url.URL, bool) {
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	i := l.find(u)
+	if i < 0 {
+		return nil, false
+	}
+	l.list[i].failures++
+	l.list[i].lastFail = now
+	for n := 1; n < len(l.list); n++ {
+		j := (i + n) % len(l.list)
+		if l.list[j].isHealthy(now) {
+			l.active = j
+			return l.list[j].url, true
+		}
+	}
+	return nil, false
+}
+
+// rewrite returns a copy of req targeting server u.
+func rewrite(req *http.Request, u *url.URL) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = u.Scheme
+	r.URL.Host = u.Host
+	r.Host = ""
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+// do executes req and fails over to alternative endpoints on network
+// errors and server side errors when more than one endpoint is configured.
+func (c *Client) do(req *request) (*http.Response, error) {
+	if c.endpoints == nil || c.endpoints.find(req.httpRequest.URL) < 0 {
+		return c.exec(req.httpRequest)
+	}
+
+	// always start with the active endpoint
+	if u := c.endpoints.current(); !(&endpoint{url: u}).matches(req.httpRequest.URL) {
+		r, err := rewrite(req.httpRequest, u)
+		if err != nil {
+			return nil, err
+		}
+		req.httpRequest = r
+	}
+
+	for tries := c.endpoints.Len(); ; tries-- {
+		resp, err := c.exec(req.httpRequest)
+		failed := isNetError(err) || (err == nil && resp.StatusCode >= 500)
+		if !failed {
+			c.endpoints.success(req.httpRequest.URL)
+			return resp, err
+		}
+		if tries <= 1 || req.httpRequest.Context().Err() != nil {
+			return resp, err
+		}
+		next, ok := c.endpoints.fail(req.httpRequest.URL)
+		if !ok {
+			return resp, err
+		}
+		c.metrics.retries.Add(1)
+		c.log.Warnf("endpoint %s failed, switching to %s", req.httpRequest.URL.Host, next.Host)
+		r, rerr := rewrite(req.httpRequest, next)
+		if rerr != nil {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		req.httpRequest = r
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"blockwatch.cc/tzgo/tezos"
 )
 
 // HTTPStatus interface represents an unprocessed HTTP reply
@@ -32,6 +34,23 @@ type HTTPError interface {
 	Body() []byte
 }
 
+// ErrInvalidAddress is returned without contacting the server when a
+// request would contain an invalid or zero address.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ErrNoData is returned when the server replies with an empty or null
+// body to a request for a single object, e.g. storage of a contract that
+// has none yet. Empty list replies are not an error.
+var ErrNoData = errors.New("no data")
+
+// CheckAddress returns ErrInvalidAddress when a is zero or malformed.
+func CheckAddress(a tezos.Address) error {
+	if !a.IsValid() {
+		return ErrInvalidAddress
+	}
+	return nil
+}
+
 var (
 	_ HTTPError = &ErrHttp{}
 	_ HTTPError = &ErrApi{}
@@ -82,19 +101,20 @@ func (e *ErrApi) UnmarshalJSON(buf []byte) error {
 		return err
 	}
 	// check if we have an embedded array and decode
-	type alias *ErrApi
+	// (alias has no methods which avoids recursion into UnmarshalJSON)
+	type alias ErrApi
 	if v, ok := t["errors"]; ok {
 		var arr []alias
 		if err := json.Unmarshal(v, &arr); err != nil {
 			return err
 		}
 		if len(arr) > 0 {
-			*e = *arr[0]
+			*e = ErrApi(arr[0])
 		}
 		return nil
 	}
 	// if not, decode as single error
-	return json.Unmarshal(buf, alias(e))
+	return json.Unmarshal(buf, (*alias)(e))
 }
 
 func (e *ErrApi) Request() string {
@@ -125,9 +145,13 @@ type ErrHttp struct {
 	statusCode int
 	body       []byte
 	header     http.Header
+	reqHeader  http.Header
 }
 
 func (e *ErrHttp) Error() string {
+	if id := e.RequestId(); id != "" {
+		return fmt.Sprintf("%d %s %s request-id=%s", e.statusCode, e.status, e.request, id)
+	}
 	return fmt.Sprintf("%d %s %s", e.statusCode, e.status, e.request)
 }
 
@@ -147,6 +171,18 @@ func (e *ErrHttp) Body() []byte {
 	return e.body
 }
 
+// RequestId returns the request id the server reported for the failed
+// request, or the id sent by the client.
+func (e *ErrHttp) RequestId() string {
+	if id := e.header.Get(RequestIdHeader); id != "" {
+		return id
+	}
+	if e.reqHeader != nil {
+		return e.reqHeader.Get(RequestIdHeader)
+	}
+	return ""
+}
+
 func (e *ErrHttp) Decode(v interface{}) error {
 	return json.Unmarshal(e.body, v)
 }
@@ -163,6 +199,7 @@ func newHttpErrorWithBody(resp *http.Response, body []byte) *ErrHttp {
 		statusCode: resp.StatusCode,
 		body:       body,
 		header:     mergeHeaders(make(http.Header), resp.Header, resp.Trailer),
+		reqHeader:  resp.Request.Header,
 	}
 }
 
@@ -232,6 +269,36 @@ func ErrorStatus(err error) int {
 	}
 }
 
+// IsNotFound reports whether err is an API or HTTP error with status 404.
+func IsNotFound(err error) bool {
+	var (
+		ae *ErrApi
+		he *ErrHttp
+	)
+	switch {
+	case errors.As(err, &ae):
+		return ae.StatusCode() == http.StatusNotFound
+	case errors.As(err, &he):
+		return he.StatusCode() == http.StatusNotFound
+	}
+	return false
+}
+
+// IsTransient reports whether err is likely to go away when the request
+// is repeated, i.e. network errors, rate limits and server errors.
+func IsTransient(err error) bool {
+	if isNetError(err) {
+		return true
+	}
+	switch code := ErrorStatus(err); {
+	case code == 427, code == http.StatusTooManyRequests:
+		return true
+	case code >= 500:
+		return true
+	}
+	return false
+}
+
 func isNetError(err error) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,154 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+var (
+	// ExportRetries is the number of times a failed page is retried by
+	// TableQuery.Export before the export gives up.
+	ExportRetries = 5
+
+	// ExportMaxBackoff limits the wait time between export retries.
+	ExportMaxBackoff = time.Minute
+)
+
+// Checkpoint persists the cursor of a resumable export.
+type Checkpoint interface {
+	// Load returns the last saved cursor or zero when nothing was saved.
+	Load() (uint64, error)
+	// Save stores cursor after a page has been fully processed.
+	Save(cursor uint64) error
+}
+
+// CheckpointFunc adapts a pair of functions to the Checkpoint interface.
+type CheckpointFunc struct {
+	LoadFn func() (uint64, error)
+	SaveFn func(uint64) error
+}
+
+func (f CheckpointFunc) Load() (uint64, error) {
+	if f.LoadFn == nil {
+		return 0, nil
+	}
+	return f.LoadFn()
+}
+
+func (f CheckpointFunc) Save(cursor uint64) error {
+	if f.SaveFn == nil {
+		return nil
+	}
+	return f.SaveFn(cursor)
+}
+
+type streamCheckpoint struct {
+	rw io.ReadWriter
+}
+
+// NewCheckpoint returns a checkpoint which appends one cursor per line to
+// rw and loads the last line on resume. Use a file opened with O_APPEND.
+func NewCheckpoint(rw io.ReadWriter) Checkpoint {
+	return &streamCheckpoint{rw: rw}
+}
+
+func (c *streamCheckpoint) Load() (uint64, error) {
+	var last string
+	scanner := bufio.NewScanner(c.rw)
+	for scanner.Scan() {
+		if s := strings.TrimSpace(scanner.Text()); s != "" {
+			last = s
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	if last == "" {
+		return 0, nil
+	}
+	cursor, err := strconv.ParseUint(last, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("checkpoint: invalid cursor %q: %w", last, err)
+	}
+	return cursor, nil
+}
+
+func (c *streamCheckpoint) Save(cursor uint64) error {
+	_, err := io.WriteString(c.rw, strconv.FormatUint(cursor, 10)+"\n")
+	return err
+}
+
+// Export pages through all rows matching q in ascending order and calls fn
+// for each row. When cp is not nil the export resumes after the cursor
+// loaded from cp and saves the cursor after each completed page. Failed
+// pages are retried up to ExportRetries times with exponential backoff.
+// Rows of a page interrupted by an error from fn are delivered again on
+// resume, so fn should be idempotent.
+func (q TableQuery[T]) Export(ctx context.Context, cp Checkpoint, fn func(T) error) error {
+	if err := q.Check(); err != nil {
+		return err
+	}
+	if cp != nil {
+		cursor, err := cp.Load()
+		if err != nil {
+			return err
+		}
+		if cursor > q.Cursor {
+			q.Cursor = cursor
+		}
+	}
+	q.Order = "asc"
+	var (
+		backoff time.Duration
+		fails   int
+	)
+	for {
+		res, err := q.Run(ctx)
+		if err != nil {
+			if ctx.Err() != nil || fails >= ExportRetries {
+				return err
+			}
+			fails++
+			backoff = util.Backoff(backoff, ExportMaxBackoff)
+			if e, ok := IsErrRateLimited(err); ok && e.Deadline() > backoff {
+				backoff = e.Deadline()
+			}
+			if !util.Sleep(ctx, backoff) {
+				return ctx.Err()
+			}
+			continue
+		}
+		fails, backoff = 0, 0
+		for _, v := range res.Rows() {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+		if res.Len() == 0 {
+			return nil
+		}
+		next := res.Cursor()
+		if next <= q.Cursor {
+			return fmt.Errorf("export: cursor did not advance past %d", q.Cursor)
+		}
+		q.Cursor = next
+		if cp != nil {
+			if err := cp.Save(q.Cursor); err != nil {
+				return err
+			}
+		}
+		if q.Limit > 0 && res.Len() < q.Limit {
+			return nil
+		}
+	}
+}
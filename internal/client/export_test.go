@@ -0,0 +1,101 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type exportRow struct {
+	RowId uint64 `json:"row_id"`
+	Name  string `json:"name"`
+}
+
+func (r exportRow) GetRowId() uint64 {
+	return r.RowId
+}
+
+func TestExportResumeFromCheckpoint(t *testing.T) {
+	const (
+		numRows  = 7
+		pageSize = 2
+		failAt   = 4 // cursor of the page that breaks mid-stream
+	)
+	var failing atomic.Bool
+	failing.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tables/test.json" {
+			http.NotFound(w, r)
+			return
+		}
+		cursor, _ := strconv.ParseUint(r.URL.Query().Get("cursor"), 10, 64)
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for id, n := cursor+1, 0; id <= numRows && n < pageSize; id, n = id+1, n+1 {
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(&buf, `[%d,"row-%d"]`, id, id)
+		}
+		buf.WriteByte(']')
+		if cursor == failAt && failing.Load() {
+			// reply breaks off in the middle of the page
+			w.Write(buf.Bytes()[:buf.Len()/2])
+			return
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	oldRetries := ExportRetries
+	ExportRetries = 0
+	defer func() { ExportRetries = oldRetries }()
+
+	c := NewClient(srv.URL, nil)
+	q := NewTableQuery[exportRow](c, "test").WithLimit(pageSize)
+	var (
+		saved uint64
+		seen  []uint64
+	)
+	cp := CheckpointFunc{
+		LoadFn: func() (uint64, error) { return saved, nil },
+		SaveFn: func(c uint64) error { saved = c; return nil },
+	}
+	collect := func(r exportRow) error {
+		seen = append(seen, r.RowId)
+		return nil
+	}
+
+	// first run fails on the third page
+	if err := q.Export(context.Background(), cp, collect); err == nil {
+		t.Fatal("expected export to fail mid-stream")
+	}
+	if len(seen) != failAt {
+		t.Fatalf("got %d rows before failure, want %d", len(seen), failAt)
+	}
+	if saved != failAt {
+		t.Fatalf("checkpoint cursor %d, want %d", saved, failAt)
+	}
+
+	// second run resumes after the saved cursor
+	failing.Store(false)
+	if err := q.Export(context.Background(), cp, collect); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(seen) != numRows {
+		t.Fatalf("got %d rows, want %d: %v", len(seen), numRows, seen)
+	}
+	for i, id := range seen {
+		if id != uint64(i+1) {
+			t.Fatalf("row %d: got id %d, want %d", i, id, i+1)
+		}
+	}
+}
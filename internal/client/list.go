@@ -0,0 +1,60 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Rowed is implemented by row types of list endpoints. GetRowId returns
+// the row id which serves as pagination cursor. Row types already export
+// the id as RowId or Id field, hence the accessor name.
+type Rowed interface {
+	GetRowId() uint64
+}
+
+// List is a page of rows returned from a list endpoint.
+type List[T Rowed] []T
+
+func (l List[T]) Len() int {
+	return len(l)
+}
+
+func (l List[T]) Last() (t T) {
+	if n := len(l); n > 0 {
+		t = l[n-1]
+	}
+	return
+}
+
+// Cursor returns the row id of the last row for use with Query.WithCursor.
+func (l List[T]) Cursor() uint64 {
+	if len(l) == 0 {
+		return 0
+	}
+	return l[len(l)-1].GetRowId()
+}
+
+// UnmarshalJSON decodes a JSON array of rows. A null reply yields an empty
+// list and null rows are dropped, so Last and Cursor are safe to use.
+func (l *List[T]) UnmarshalJSON(buf []byte) error {
+	var rows []json.RawMessage
+	if err := json.Unmarshal(buf, &rows); err != nil {
+		return err
+	}
+	list := make(List[T], 0, len(rows))
+	for _, row := range rows {
+		if bytes.Equal(row, []byte("null")) {
+			continue
+		}
+		var t T
+		if err := json.Unmarshal(row, &t); err != nil {
+			return err
+		}
+		list = append(list, t)
+	}
+	*l = list
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type listRow struct {
+	Id uint64 `json:"id"`
+}
+
+func (r *listRow) GetRowId() uint64 {
+	return r.Id
+}
+
+func TestListUnmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		len    int
+		cursor uint64
+	}{
+		{"null", `null`, 0, 0},
+		{"empty", `[]`, 0, 0},
+		{"rows", `[{"id":1},{"id":7}]`, 2, 7},
+		{"null rows", `[{"id":3},null,{"id":5},null]`, 2, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var l List[*listRow]
+			if err := json.Unmarshal([]byte(tt.in), &l); err != nil {
+				t.Fatal(err)
+			}
+			if got := l.Len(); got != tt.len {
+				t.Errorf("len %d, want %d", got, tt.len)
+			}
+			if got := l.Cursor(); got != tt.cursor {
+				t.Errorf("cursor %d, want %d", got, tt.cursor)
+			}
+		})
+	}
+}
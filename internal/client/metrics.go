@@ -0,0 +1,39 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"sync/atomic"
+)
+
+// Metrics is a snapshot of client counters. All counters are cumulative
+// since the client was created and are safe to read periodically, e.g.
+// to export them as Prometheus counters.
+type Metrics struct {
+	Requests      int64 // API calls, incremented once per call regardless of retries
+	Retries       int64 // repeated attempts after network errors and endpoint failovers
+	Errors        int64 // API calls which returned an error, including HTTP status >= 400
+	BytesReceived int64 // response body bytes read, including error responses
+	CacheHits     int64 // script cache lookups which found an entry
+	CacheMisses   int64 // script cache lookups which missed or found an expired entry
+}
+
+type metrics struct {
+	requests atomic.Int64
+	retries  atomic.Int64
+	errors   atomic.Int64
+	bytes    atomic.Int64
+}
+
+// Metrics returns a snapshot of request and cache counters.
+func (c *Client) Metrics() Metrics {
+	return Metrics{
+		Requests:      c.metrics.requests.Load(),
+		Retries:       c.metrics.retries.Load(),
+		Errors:        c.metrics.errors.Load(),
+		BytesReceived: c.metrics.bytes.Load(),
+		CacheHits:     c.stats.hits.Load(),
+		CacheMisses:   c.stats.misses.Load(),
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+var errMsgpackShort = errors.New("msgpack: unexpected end of data")
+
+// decodeMsgpack decodes a msgpack reply into v. The reply is translated
+// into JSON first so result types decode the same way as JSON replies
+// including custom UnmarshalJSON methods. Binary strings are decoded as
+// base64 strings, timestamps as RFC3339 strings.
+func decodeMsgpack(buf []byte, v any) error {
+	d := msgpackDecoder{buf: buf}
+	val, err := d.decode()
+	if err != nil {
+		return err
+	}
+	if d.pos != len(buf) {
+		return fmt.Errorf("msgpack: %d trailing bytes", len(buf)-d.pos)
+	}
+	js, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, v)
+}
+
+type msgpackDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.buf) {
+		return nil, errMsgpackShort
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// uint reads an n byte big endian unsigned integer.
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *msgpackDecoder) decode() (any, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.decodeString(int(c & 0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), b...), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(int(n))
+	case 0xca:
+		v, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (c - 0xd0)
+		v, err := d.uint(n)
+		if err != nil {
+			return nil, err
+		}
+		// sign extend
+		shift := 64 - 8*n
+		return int64(v<<shift) >> shift, nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: invalid type byte 0x%02x", c)
+}
+
+func (d *msgpackDecoder) decodeString(n int) (any, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (d *msgpackDecoder) decodeArray(n int) (any, error) {
+	if n > len(d.buf)-d.pos {
+		return nil, errMsgpackShort
+	}
+	list := make([]any, n)
+	for i := range list {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		list[i] = v
+	}
+	return list, nil
+}
+
+// decodeMap decodes a map into a JSON object. Non-string keys use their
+// string form like JSON object keys.
+func (d *msgpackDecoder) decodeMap(n int) (any, error) {
+	if n > len(d.buf)-d.pos {
+		return nil, errMsgpackShort
+	}
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		switch key := k.(type) {
+		case string:
+			m[key] = v
+		case int64:
+			m[strconv.FormatInt(key, 10)] = v
+		case uint64:
+			m[strconv.FormatUint(key, 10)] = v
+		default:
+			m[fmt.Sprint(key)] = v
+		}
+	}
+	return m, nil
+}
+
+// decodeExt decodes extension types. Only the timestamp extension is
+// supported.
+func (d *msgpackDecoder) decodeExt(n int) (any, error) {
+	b, err := d.next(n + 1)
+	if err != nil {
+		return nil, err
+	}
+	typ, data := int8(b[0]), b[1:]
+	if typ != -1 {
+		return nil, fmt.Errorf("msgpack: unsupported extension type %d", typ)
+	}
+	var sec, nsec int64
+	switch len(data) {
+	case 4:
+		sec = int64(binary.BigEndian.Uint32(data))
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		nsec, sec = int64(v>>34), int64(v&(1<<34-1))
+	case 12:
+		nsec = int64(binary.BigEndian.Uint32(data))
+		sec = int64(binary.BigEndian.Uint64(data[4:]))
+	default:
+		return nil, fmt.Errorf("msgpack: invalid timestamp length %d", len(data))
+	}
+	return time.Unix(sec, nsec).UTC(), nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// {"a":1,"b":[true,"x",nil],"c":-3,"d":1.5,"e":-256,"f":65536}
+var msgpackTestBody = []byte{
+	0x86,
+	0xa1, 'a', 0x01,
+	0xa1, 'b', 0x93, 0xc3, 0xa1, 'x', 0xc0,
+	0xa1, 'c', 0xfd,
+	0xa1, 'd', 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0,
+	0xa1, 'e', 0xd1, 0xff, 0x00,
+	0xa1, 'f', 0xce, 0x00, 0x01, 0x00, 0x00,
+}
+
+type msgpackTestResult struct {
+	A int64   `json:"a"`
+	B []any   `json:"b"`
+	C int     `json:"c"`
+	D float64 `json:"d"`
+	E int16   `json:"e"`
+	F uint32  `json:"f"`
+}
+
+func TestDecodeMsgpack(t *testing.T) {
+	var res msgpackTestResult
+	if err := decodeMsgpack(msgpackTestBody, &res); err != nil {
+		t.Fatal(err)
+	}
+	want := msgpackTestResult{A: 1, B: []any{true, "x", nil}, C: -3, D: 1.5, E: -256, F: 65536}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	// timestamp 32 extension
+	var ts time.Time
+	if err := decodeMsgpack([]byte{0xd6, 0xff, 0x65, 0x92, 0x00, 0x80}, &ts); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Unix(0x65920080, 0).UTC(); !ts.Equal(want) {
+		t.Errorf("got time %s, want %s", ts, want)
+	}
+
+	for _, buf := range [][]byte{
+		{0x92, 0x01},       // short array
+		{0xa3, 'a'},        // short string
+		{0xc1},             // never used
+		{0x01, 0x02},       // trailing data
+		{0xd4, 0x01, 0x00}, // unknown extension
+	} {
+		var v any
+		if err := decodeMsgpack(buf, &v); err == nil {
+			t.Errorf("%x: expected error", buf)
+		}
+	}
+}
+
+func TestGetQueryFormat(t *testing.T) {
+	var accept, query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept, query = r.Header.Get("Accept"), r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/msgpack")
+		w.Write(msgpackTestBody)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, nil).WithDefaultParams(NewQuery().WithLimit(10))
+	q := NewQuery().WithPath("/test").WithFormat(FormatMsgpack)
+	var res msgpackTestResult
+	if err := c.GetQuery(context.Background(), q, nil, &res); err != nil {
+		t.Fatal(err)
+	}
+	if accept != FormatMsgpack.MimeType() {
+		t.Errorf("got Accept %q, want %q", accept, FormatMsgpack.MimeType())
+	}
+	if query != "limit=10" {
+		t.Errorf("got query %q, want limit=10", query)
+	}
+	if res.F != 65536 {
+		t.Errorf("reply not decoded: %+v", res)
+	}
+	if !q.Query.Has(formatArg) || q.Query.Has("limit") {
+		t.Errorf("send modified the caller's query: %v", q.Query)
+	}
+}
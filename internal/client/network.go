@@ -0,0 +1,56 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"blockwatch.cc/tzgo/tezos"
+)
+
+// networkCheck verifies once that the server indexes the expected chain.
+type networkCheck struct {
+	sync.Mutex
+	want tezos.ChainIdHash
+	done bool
+	err  error
+}
+
+// WithNetwork pins the client to a chain. Before the first request the
+// server's chain id is checked and all requests fail on mismatch.
+func (c *Client) WithNetwork(id tezos.ChainIdHash) *Client {
+	if id.IsValid() {
+		c.network = &networkCheck{want: id}
+	} else {
+		c.network = nil
+	}
+	return c
+}
+
+func (c *Client) checkNetwork(ctx context.Context) error {
+	n := c.network
+	if n == nil {
+		return nil
+	}
+	n.Lock()
+	defer n.Unlock()
+	if n.done {
+		return n.err
+	}
+	var tip struct {
+		ChainId tezos.ChainIdHash `json:"chain_id"`
+	}
+	if err := c.send(ctx, http.MethodGet, pathQuery("/explorer/tip"), nil, nil, &tip).Receive(ctx); err != nil {
+		// transient errors are not cached
+		return fmt.Errorf("network check: %w", err)
+	}
+	n.done = true
+	if !tip.ChainId.Equal(n.want) {
+		n.err = fmt.Errorf("network mismatch: server chain %s, expected %s", tip.ChainId, n.want)
+	}
+	return n.err
+}
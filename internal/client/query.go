@@ -10,13 +10,19 @@ import (
 	"strings"
 	"time"
 
+	"blockwatch.cc/tzgo/tezos"
 	"blockwatch.cc/tzpro-go/internal/util"
 )
 
+// Query holds server, path and arguments of an API request. Builder
+// methods modify the shared argument map in place, so a Query is not safe
+// to reuse or share between goroutines once it is modified. Clone it
+// before deriving new requests from a common base.
 type Query struct {
 	Server string
 	Path   string
 	Query  url.Values
+	err    error
 }
 
 func NewQuery() Query {
@@ -55,14 +61,16 @@ func ParseQuery(s string) (Query, error) {
 	return p, nil
 }
 
+// Clone returns a deep copy of p including its argument values.
 func (p Query) Clone() Query {
 	np := Query{
 		Query: url.Values{},
 	}
 	np.Server = p.Server
 	np.Path = p.Path
+	np.err = p.err
 	for n, v := range p.Query {
-		np.Query[n] = v
+		np.Query[n] = append([]string(nil), v...)
 	}
 	return np
 }
@@ -122,11 +130,64 @@ func (p Query) AndNotIn(key string, val ...any) Query {
 	return p
 }
 
+// WithAny matches rows where field equals any of values. The server only
+// supports OR semantics within a single field through the in (and nin)
+// operators, OR across different fields is not available. Field must be
+// a plain column name without operator suffix and values must not
+// contain commas. Otherwise any request built from the query fails with
+// the validation error instead of returning unfiltered data.
+func (p Query) WithAny(field string, values ...string) Query {
+	val, err := joinAny(field, values)
+	if err != nil {
+		if p.err == nil {
+			p.err = err
+		}
+		return p
+	}
+	p.Query.Set(field+".in", val)
+	return p
+}
+
+// joinAny validates a WithAny filter and returns its encoded value.
+func joinAny(field string, values []string) (string, error) {
+	switch {
+	case field == "":
+		return "", fmt.Errorf("any: empty field name")
+	case strings.Contains(field, "."):
+		return "", fmt.Errorf("any: field %q must not contain an operator, only in supports OR", field)
+	case len(values) == 0:
+		return "", fmt.Errorf("any: no values for field %q", field)
+	}
+	for _, v := range values {
+		if v == "" || strings.Contains(v, ",") {
+			return "", fmt.Errorf("any: invalid value %q for field %q", v, field)
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
 func (p Query) AndRange(key string, from, to any) Query {
 	p.Query.Set(key+".rg", util.ToString([]any{from, to}))
 	return p
 }
 
+// AndTimeRange filters time column key to the closed interval [from, to].
+// A zero from or to time leaves the respective end of the range open.
+func (p Query) AndTimeRange(key string, from, to time.Time) Query {
+	for _, m := range []string{".rg", ".gte", ".lte"} {
+		p.Query.Del(key + m)
+	}
+	switch {
+	case !from.IsZero() && !to.IsZero():
+		p.Query.Set(key+".rg", from.UTC().Format(time.RFC3339)+","+to.UTC().Format(time.RFC3339))
+	case !from.IsZero():
+		p.Query.Set(key+".gte", from.UTC().Format(time.RFC3339))
+	case !to.IsZero():
+		p.Query.Set(key+".lte", to.UTC().Format(time.RFC3339))
+	}
+	return p
+}
+
 func (p Query) AndRegexp(key string, re string) Query {
 	p.Query.Set(key+".re", re)
 	return p
@@ -142,16 +203,33 @@ func (p Query) AndNot(key string) Query {
 	return p
 }
 
+// MaxLimit is the largest page size accepted by the server.
+var MaxLimit uint = 50000
+
+// WithLimit sets the page size. Values above MaxLimit are rejected by the
+// server and record a builder error instead.
 func (p Query) WithLimit(v uint) Query {
+	if v > MaxLimit {
+		if p.err == nil {
+			p.err = fmt.Errorf("limit %d exceeds maximum %d", v, MaxLimit)
+		}
+		return p
+	}
 	p.Query.Set("limit", strconv.Itoa(int(v)))
 	return p
 }
 
+// WithOffset skips v results. Offset paging is simple but slows down with
+// growing offsets and may skip or repeat rows when data changes between
+// requests. Prefer WithCursor for large or live result sets.
 func (p Query) WithOffset(v uint) Query {
 	p.Query.Set("offset", strconv.Itoa(int(v)))
 	return p
 }
 
+// WithCursor continues a listing after the row with id v, usually the
+// Cursor() of the previous page. Cursor paging is stable under inserts
+// and fast at any depth, but only works with a fixed sort order.
 func (p Query) WithCursor(v uint64) Query {
 	p.Query.Set("cursor", strconv.FormatUint(v, 10))
 	return p
@@ -172,6 +250,15 @@ func (p Query) Asc() Query {
 	return p
 }
 
+// WithSort orders results by field. Not all endpoints support sorting.
+func (p Query) WithSort(field string, desc bool) Query {
+	p.Query.Set("order_by", field)
+	if desc {
+		return p.Desc()
+	}
+	return p.Asc()
+}
+
 func (p Query) WithMeta() Query {
 	p.Query.Set("meta", "1")
 	return p
@@ -213,6 +300,11 @@ func (p Query) WithStorage() Query {
 	return p
 }
 
+// WithUnpack asks the server to decode PACKed bytes in storage and
+// parameters. Contract storage calls also request prim data and unpack
+// leftover packed fields on the client using the contract's storage type.
+// Recursive unpacking re-renders the full value and can be slow on large
+// storage, only use it when packed data is expected.
 func (p Query) WithUnpack() Query {
 	p.Query.Set("unpack", "1")
 	return p
@@ -223,11 +315,74 @@ func (p Query) WithRights() Query {
 	return p
 }
 
+// WithEntrypoint limits contract call listings to calls of entrypoint name.
+func (p Query) WithEntrypoint(name string) Query {
+	return p.WithEntrypoints(name)
+}
+
+// WithEntrypoints limits contract call listings to calls of any of the
+// named entrypoints. The filter combines with time range, order and
+// cursor arguments.
+func (p Query) WithEntrypoints(names ...string) Query {
+	if len(names) == 0 {
+		p.Query.Del("entrypoint")
+		return p
+	}
+	p.Query.Set("entrypoint", strings.Join(names, ","))
+	return p
+}
+
+// WithSender limits op listings to operations sent by addr.
+func (p Query) WithSender(addr tezos.Address) Query {
+	return p.withAddress("sender", addr)
+}
+
+// WithReceiver limits op listings to operations received by addr.
+func (p Query) WithReceiver(addr tezos.Address) Query {
+	return p.withAddress("receiver", addr)
+}
+
+func (p Query) withAddress(key string, addr tezos.Address) Query {
+	if !addr.IsValid() {
+		if p.err == nil {
+			p.err = fmt.Errorf("%s: %w", key, ErrInvalidAddress)
+		}
+		return p
+	}
+	p.Query.Set(key, addr.String())
+	return p
+}
+
+// Err returns the first error recorded by a builder, e.g. an invalid
+// address filter. Requests built from a query with error fail before
+// they are sent.
+func (p Query) Err() error {
+	return p.err
+}
+
+// WithFormat requests the reply in format f using the Accept header.
+// JSON stays the default. JSON and msgpack replies are decoded into typed
+// results, CSV replies must be read into an io.Writer, e.g. with Client.Do
+// or TableQuery.Stream. Unsupported formats fail the request before it is
+// sent.
+func (p Query) WithFormat(f FormatType) Query {
+	if !f.IsValid() {
+		if p.err == nil {
+			p.err = fmt.Errorf("unsupported format '%s'", f)
+		}
+		return p
+	}
+	p.Query.Set(formatArg, string(f))
+	return p
+}
+
 func (p Query) WithPath(path string) Query {
 	p.Path = path
 	return p
 }
 
+// Url returns the request URL. Builder errors are not part of the URL,
+// check Err or send the query with Client.GetQuery which fails on them.
 func (p Query) Url() string {
 	var b strings.Builder
 	b.WriteString(p.Server)
@@ -243,6 +398,9 @@ func (p Query) Url() string {
 }
 
 func (p Query) Check() error {
+	if p.err != nil {
+		return p.err
+	}
 	if p.Server == "" {
 		return fmt.Errorf("empty server URL")
 	}
@@ -0,0 +1,181 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SensitiveHeaders are removed from recorded requests and responses.
+var SensitiveHeaders = []string{
+	"X-Api-Key",
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+type tapeMode byte
+
+const (
+	tapeRecord tapeMode = iota + 1
+	tapeReplay
+)
+
+// tape records HTTP exchanges to dir or replays them from there.
+type tape struct {
+	dir  string
+	mode tapeMode
+}
+
+// recording is the on-disk format of a single HTTP exchange. JSON bodies
+// are stored as is for readability, other bodies base64 encoded.
+type recording struct {
+	Method    string          `json:"method"`
+	Url       string          `json:"url"`
+	ReqHeader http.Header     `json:"request_header,omitempty"`
+	ReqBody   []byte          `json:"request_body,omitempty"`
+	Status    int             `json:"status"`
+	Header    http.Header     `json:"header,omitempty"`
+	Body      json.RawMessage `json:"body,omitempty"`
+	Data      []byte          `json:"data,omitempty"`
+}
+
+// WithRecorder writes every request URL and response to a file in dir
+// for debugging and later replay with WithReplay. Sensitive headers like
+// the API key are not recorded.
+func (c *Client) WithRecorder(dir string) *Client {
+	c.tape = &tape{dir: dir, mode: tapeRecord}
+	return c
+}
+
+// WithReplay serves responses from recordings in dir instead of the
+// network. Requests without recording fail. Recordings are matched by
+// method, path, query and request body regardless of server.
+func (c *Client) WithReplay(dir string) *Client {
+	c.tape = &tape{dir: dir, mode: tapeReplay}
+	return c
+}
+
+// exec sends r through the configured transport or tape.
+func (c *Client) exec(r *http.Request) (*http.Response, error) {
+	if c.tape == nil {
+		return c.transport.Do(r)
+	}
+	return c.tape.do(c.transport, r)
+}
+
+func (t *tape) do(hc *http.Client, r *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		buf, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = buf
+		r.Body = io.NopCloser(bytes.NewReader(buf))
+	}
+	name := filepath.Join(t.dir, tapeKey(r, reqBody)+".json")
+
+	if t.mode == tapeReplay {
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("replay: no recording for %s %s", r.Method, r.URL.RequestURI())
+			}
+			return nil, err
+		}
+		var rec recording
+		if err := json.Unmarshal(buf, &rec); err != nil {
+			return nil, fmt.Errorf("replay: %s: %w", name, err)
+		}
+		body := rec.Data
+		if len(rec.Body) > 0 {
+			body = rec.Body
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+			StatusCode:    rec.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        rec.Header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       r,
+		}, nil
+	}
+
+	resp, err := hc.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	rec := recording{
+		Method:    r.Method,
+		Url:       r.URL.String(),
+		ReqHeader: scrubHeader(r.Header),
+		ReqBody:   reqBody,
+		Status:    resp.StatusCode,
+		Header:    scrubHeader(resp.Header),
+	}
+	if json.Valid(body) {
+		rec.Body = body
+	} else {
+		rec.Data = body
+	}
+	if err := writeRecording(name, &rec); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// tapeKey identifies a request by method, path, query and body.
+func tapeKey(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return strings.ToLower(r.Method) + "-" + hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+func scrubHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	h = h.Clone()
+	for _, k := range SensitiveHeaders {
+		h.Del(k)
+	}
+	return h
+}
+
+// writeRecording atomically replaces the file at name.
+func writeRecording(name string, rec *recording) error {
+	buf, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	const apiKey = "secret-test-key"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"height":42,"query":"` + r.URL.RawQuery + `"}`))
+		case "/post":
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"echo":` + string(body) + `}`))
+		case "/msgpack":
+			w.Header().Set("Content-Type", "application/msgpack")
+			w.Write(msgpackTestBody)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		data   any
+	}{
+		{"get json", http.MethodGet, "/json?limit=1", nil},
+		{"get json other query", http.MethodGet, "/json?limit=2", nil},
+		{"post json", http.MethodPost, "/post", map[string]int{"n": 1}},
+		{"post json other body", http.MethodPost, "/post", map[string]int{"n": 2}},
+		{"get msgpack", http.MethodGet, "/msgpack", nil},
+	}
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	rec := NewClient(srv.URL, nil).WithApiKey(apiKey).WithRecorder(dir)
+	recorded := make([]any, len(tests))
+	for i, tt := range tests {
+		var res any
+		if err := rec.call(ctx, tt.method, NewQuery().WithPath(tt.path), nil, tt.data, &res); err != nil {
+			t.Fatalf("%s: record: %v", tt.name, err)
+		}
+		recorded[i] = res
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(tests) {
+		t.Errorf("got %d recordings, want %d", len(files), len(tests))
+	}
+	for _, name := range files {
+		buf, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(buf, []byte(apiKey)) {
+			t.Errorf("%s: recording contains the API key", filepath.Base(name))
+		}
+	}
+
+	// replay must not touch the network and ignores the server address
+	srv.Close()
+	play := NewClient("http://replay.invalid", nil).WithReplay(dir)
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res any
+			if err := play.call(ctx, tt.method, NewQuery().WithPath(tt.path), nil, tt.data, &res); err != nil {
+				t.Fatalf("replay: %v", err)
+			}
+			if !reflect.DeepEqual(res, recorded[i]) {
+				t.Errorf("got %v, want %v", res, recorded[i])
+			}
+		})
+	}
+
+	var res any
+	if err := play.Get(ctx, "/json?limit=3", nil, &res); err == nil {
+		t.Error("expected error for request without recording")
+	}
+}
@@ -86,11 +86,24 @@ func (r FutureResult) Receive(ctx context.Context) error {
 			return e
 		}
 		if e, ok := IsErrHttp(err); ok {
+			he := e.(*ErrHttp)
 			var ae ErrApi
-			if err := e.(*ErrHttp).Decode(&ae); err == nil {
-				ae.Request_ = e.(*ErrHttp).Request()
+			if err := he.Decode(&ae); err == nil {
+				ae.Request_ = he.Request()
+				if ae.Status_ == 0 {
+					ae.Status_ = he.StatusCode()
+				}
 				return &ae
 			}
+			// not found replies without error body still become API errors
+			if he.StatusCode() == http.StatusNotFound {
+				return &ErrApi{
+					Status_:   he.StatusCode(),
+					Message:   http.StatusText(he.StatusCode()),
+					RequestId: he.RequestId(),
+					Request_:  he.Request(),
+				}
+			}
 			return e
 		}
 		return err
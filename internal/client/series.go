@@ -0,0 +1,133 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+const (
+	FillModeInvalid FillMode = ""
+	FillModeNone    FillMode = "none"
+	FillModeNull    FillMode = "null"
+	FillModeLast    FillMode = "last"
+	FillModeLinear  FillMode = "linear"
+	FillModeZero    FillMode = "zero"
+)
+
+func (m FillMode) IsValid() bool {
+	switch m {
+	case FillModeNone, FillModeNull, FillModeLast, FillModeLinear, FillModeZero:
+		return true
+	}
+	return false
+}
+
+// SeriesParams controls aggregation interval, time range, gap filling and
+// columns of time-series requests. Zero values leave the respective server
+// default in place. Without a fill mode the server applies FillModeNone and
+// intervals without data are omitted, so consecutive points may be spaced
+// unevenly.
+type SeriesParams struct {
+	Collapse time.Duration
+	From     time.Time
+	To       time.Time
+	Fill     FillMode
+	Columns  []string
+	Limit    int
+}
+
+func NewSeriesParams() SeriesParams {
+	return SeriesParams{}
+}
+
+// WithFill sets how intervals without data are filled. Null emits empty
+// points, zero emits zero values, last repeats the previous point and
+// linear interpolates between neighbouring points.
+func (p SeriesParams) WithFill(mode FillMode) SeriesParams {
+	p.Fill = mode
+	return p
+}
+
+// WithCollapse sets the aggregation interval used to downsample the series.
+func (p SeriesParams) WithCollapse(interval time.Duration) SeriesParams {
+	p.Collapse = interval
+	return p
+}
+
+// WithRange limits the series to the time range [from, to].
+func (p SeriesParams) WithRange(from, to time.Time) SeriesParams {
+	p.From, p.To = from, to
+	return p
+}
+
+// WithColumns selects the series columns to return.
+func (p SeriesParams) WithColumns(cols ...string) SeriesParams {
+	p.Columns = cols
+	return p
+}
+
+// Check returns an error when params contain an unsupported fill mode.
+func (p SeriesParams) Check() error {
+	if p.Fill != FillModeInvalid && !p.Fill.IsValid() {
+		return fmt.Errorf("invalid fill mode %q", p.Fill)
+	}
+	return nil
+}
+
+// Query returns a query for the series endpoint at path.
+func (p SeriesParams) Query(path string) Query {
+	q := NewQuery().WithPath(path)
+	if p.Limit > 0 {
+		q.Query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if len(p.Columns) > 0 {
+		q.Query.Set("columns", strings.Join(p.Columns, ","))
+	}
+	if p.Fill != "" {
+		q.Query.Set("fill", string(p.Fill))
+	}
+	if p.Collapse > 0 {
+		q.Query.Set("collapse", util.ShortDurationString(p.Collapse.String()))
+	}
+	if !p.From.IsZero() {
+		q.Query.Set("start_date", p.From.Format(time.RFC3339))
+	}
+	if !p.To.IsZero() {
+		q.Query.Set("end_date", p.To.Format(time.RFC3339))
+	}
+	return q
+}
+
+// QuerySeries loads the time-series at path and decodes rows into T. When
+// params contains no columns all non-ignored fields of T are requested.
+func QuerySeries[T any](ctx context.Context, c *Client, path string, params SeriesParams) ([]*T, error) {
+	if err := params.Check(); err != nil {
+		return nil, err
+	}
+	if len(params.Columns) == 0 {
+		var t T
+		tinfo, err := getTypeInfo(t)
+		if err != nil {
+			return nil, err
+		}
+		params.Columns = tinfo.FilteredAliases(fieldFlagIgnore)
+	}
+	var data json.RawMessage
+	if err := c.GetQuery(ctx, params.Query(path), nil, &data); err != nil {
+		return nil, err
+	}
+	res := make([]*T, 0)
+	if err := DecodeSlice(data, params.Columns, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -6,13 +6,15 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 
-	// "io"
-	// "net/http"
-	"reflect"
+	"io"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"blockwatch.cc/tzpro-go/internal/util"
 )
@@ -41,6 +43,34 @@ type OrderType string
 
 type FormatType string
 
+const (
+	FormatJSON    FormatType = "json"
+	FormatCSV     FormatType = "csv"
+	FormatMsgpack FormatType = "msgpack"
+)
+
+// IsValid reports whether the SDK supports reply format f.
+func (f FormatType) IsValid() bool {
+	switch f {
+	case FormatJSON, FormatCSV, FormatMsgpack:
+		return true
+	default:
+		return false
+	}
+}
+
+// MimeType returns the content type requested for format f.
+func (f FormatType) MimeType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatMsgpack:
+		return "application/msgpack"
+	default:
+		return "application/json"
+	}
+}
+
 // type TableQueryAPI interface {
 // 	WithFilter(mode FilterMode, col string, val ...any) TableQueryAPI
 // 	ReplaceFilter(mode FilterMode, col string, val ...any) TableQueryAPI
@@ -58,10 +88,10 @@ type FormatType string
 // 	Url() string
 // }
 
-type TableQuery[T any] struct {
+type TableQuery[T Rowed] struct {
 	Query   Query
 	Table   string     // "op", "block", "chain", "flow"
-	Format  FormatType // "json", "csv"
+	Format  FormatType // "json", "csv", "msgpack"
 	Columns []string
 	Limit   int
 	Cursor  uint64
@@ -70,12 +100,11 @@ type TableQuery[T any] struct {
 	NoFail  bool
 	Filter  FilterList
 	Order   OrderType // asc, desc
-	// OrderBy string // column name
-	// Sort string // asc/desc
-	client *Client
+	SortBy  string    // column name, empty means row_id
+	client  *Client
 }
 
-func NewTableQuery[T any](c *Client, name string) *TableQuery[T] {
+func NewTableQuery[T Rowed](c *Client, name string) *TableQuery[T] {
 	var t T
 	tinfo, err := getTypeInfo(t)
 	if err != nil {
@@ -147,11 +176,69 @@ func (q *TableQuery[T]) AndRange(col string, from, to any) *TableQuery[T] {
 	return q
 }
 
+// AndTimeRange filters time column col to the closed interval [from, to].
+// A zero from or to time leaves the respective end of the range open.
+func (q *TableQuery[T]) AndTimeRange(col string, from, to time.Time) *TableQuery[T] {
+	switch {
+	case !from.IsZero() && !to.IsZero():
+		q.Filter.Add("rg", col, from.UTC(), to.UTC())
+	case !from.IsZero():
+		q.Filter.Add("gte", col, from.UTC())
+	case !to.IsZero():
+		q.Filter.Add("lte", col, to.UTC())
+	}
+	return q
+}
+
 func (q *TableQuery[T]) AndRegexp(col string, re string) *TableQuery[T] {
 	q.Filter.Add("re", col, re)
 	return q
 }
 
+// WithAny matches rows where col equals any of values using the in
+// operator. See Query.WithAny for restrictions.
+func (q *TableQuery[T]) WithAny(col string, values ...string) *TableQuery[T] {
+	val, err := joinAny(col, values)
+	if err != nil {
+		if q.Query.err == nil {
+			q.Query.err = err
+		}
+		return q
+	}
+	q.Filter.Add("in", col, val)
+	return q
+}
+
+// WithCodeHash filters rows by hex encoded code hash h, e.g. to find all
+// deployments of a contract template.
+func (q *TableQuery[T]) WithCodeHash(h string) *TableQuery[T] {
+	return q.andHash("code_hash", h)
+}
+
+// WithStorageHash filters rows by hex encoded storage hash h.
+func (q *TableQuery[T]) WithStorageHash(h string) *TableQuery[T] {
+	return q.andHash("storage_hash", h)
+}
+
+// WithInterfaceHash filters rows by hex encoded interface hash h which is
+// shared by contracts with identical entrypoints.
+func (q *TableQuery[T]) WithInterfaceHash(h string) *TableQuery[T] {
+	return q.andHash("iface_hash", h)
+}
+
+// andHash adds an equality filter for a hex hash column. Malformed hashes
+// are reported by Check and fail the query before it is sent.
+func (q *TableQuery[T]) andHash(col, h string) *TableQuery[T] {
+	if _, err := hex.DecodeString(h); err != nil || h == "" {
+		if q.Query.err == nil {
+			q.Query.err = fmt.Errorf("invalid %s %q", col, h)
+		}
+		return q
+	}
+	q.Filter.Add("eq", col, h)
+	return q
+}
+
 func (q *TableQuery[T]) ReplaceFilter(mode FilterMode, col string, val ...any) *TableQuery[T] {
 	for i, v := range q.Filter {
 		if v.Column == col {
@@ -194,6 +281,23 @@ func (q *TableQuery[T]) Asc() *TableQuery[T] {
 	return q
 }
 
+// OrderBy sorts results by column field in ascending or descending order.
+// The field must be a column alias of the result type T. Note that cursors
+// always refer to row ids, so when sorting by any other column a cursor
+// only paginates correctly as long as the sort column is monotonic in
+// row_id (e.g. first_seen). For other columns paginate with WithOffset
+// on the underlying Query or add a range filter on the sort column using
+// the last seen value.
+func (q *TableQuery[T]) OrderBy(field string, desc bool) *TableQuery[T] {
+	q.SortBy = field
+	if desc {
+		q.Order = "desc"
+	} else {
+		q.Order = "asc"
+	}
+	return q
+}
+
 func (q *TableQuery[T]) WithVerbose() *TableQuery[T] {
 	q.Verbose = true
 	return q
@@ -231,6 +335,9 @@ func (p TableQuery[T]) Check() error {
 	if p.Table == "" {
 		return fmt.Errorf("empty table name")
 	}
+	if p.Limit < 0 || uint(p.Limit) > MaxLimit {
+		return fmt.Errorf("limit %d out of range [0, %d]", p.Limit, MaxLimit)
+	}
 	for _, v := range p.Filter {
 		if v.Column == "" {
 			return fmt.Errorf("empty filter column name")
@@ -242,16 +349,28 @@ func (p TableQuery[T]) Check() error {
 			return fmt.Errorf("empty value for filter column '%s'", v.Column)
 		}
 	}
-	switch p.Format {
-	case "json", "csv", "":
-		// OK
-	default:
+	if p.SortBy != "" {
+		var t T
+		tinfo, err := getTypeInfo(t)
+		if err != nil {
+			return err
+		}
+		if f, ok := tinfo.Find(p.SortBy); !ok || f.Alias != p.SortBy {
+			return fmt.Errorf("unknown order column '%s'", p.SortBy)
+		}
+	}
+	if p.Format != "" && !p.Format.IsValid() {
 		return fmt.Errorf("unsupported format '%s'", p.Format)
 	}
 	return nil
 }
 
 func (p TableQuery[T]) Url() string {
+	return p.query().Url()
+}
+
+// query returns the request query including table arguments.
+func (p TableQuery[T]) query() Query {
 	base := p.Query.Clone()
 	if p.Cursor > 0 {
 		base.Query.Set("cursor", strconv.FormatUint(p.Cursor, 10))
@@ -269,45 +388,120 @@ func (p TableQuery[T]) Url() string {
 		base.Query.Set(v.Column+"."+string(v.Mode), util.ToString(v.Value))
 	}
 	base.Query.Set("order", string(p.Order))
+	if p.SortBy != "" {
+		base.Query.Set("order_by", p.SortBy)
+	}
+	// binary formats are selected by Accept header only
 	format := p.Format
-	if format == "" {
-		format = "json"
+	if format == "" || format == FormatMsgpack {
+		format = FormatJSON
 	}
-	return base.WithPath("tables/" + p.Table + "." + string(format)).Url()
+	return base.WithPath("tables/" + p.Table + "." + string(format))
 }
 
 func (q TableQuery[T]) Run(ctx context.Context) (*TableQueryResult[T], error) {
 	if err := q.Check(); err != nil {
 		return nil, err
 	}
+	format := q.Format
+	switch format {
+	case "":
+		format = FormatJSON
+	case FormatJSON, FormatMsgpack:
+	default:
+		return nil, fmt.Errorf("format %s cannot be decoded, use Stream", q.Format)
+	}
+	h := make(http.Header)
+	h.Set("Accept", format.MimeType())
 	res := NewTableQueryResult[T](q.Columns)
-	if err := q.client.Get(ctx, q.Url(), nil, res); err != nil {
+	if err := q.client.GetQuery(ctx, q.query(), h, res); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
-type TableQueryResult[T any] struct {
+// Stream writes the raw reply in the query's format to w without
+// decoding. Use it for bulk CSV or msgpack transfers.
+func (q TableQuery[T]) Stream(ctx context.Context, w io.Writer) error {
+	if err := q.Check(); err != nil {
+		return err
+	}
+	format := q.Format
+	if format == "" {
+		format = FormatJSON
+	}
+	h := make(http.Header)
+	h.Set("Accept", format.MimeType())
+	return q.client.GetQuery(ctx, q.query(), h, w)
+}
+
+type TableQueryResult[T Rowed] struct {
 	rows    []T
 	columns []string
 }
 
-func NewTableQueryResult[T any](cols []string) *TableQueryResult[T] {
+func NewTableQueryResult[T Rowed](cols []string) *TableQueryResult[T] {
 	return &TableQueryResult[T]{
 		rows:    make([]T, 0),
 		columns: cols,
 	}
 }
 
+func (r *TableQueryResult[T]) listResult() {}
+
+// tableQueryResultJSON is the self-describing encoding produced by
+// MarshalJSON which retains result columns across a JSON round-trip.
+type tableQueryResultJSON[T Rowed] struct {
+	Columns []string `json:"columns"`
+	Rows    []T      `json:"rows"`
+}
+
 func (r *TableQueryResult[T]) UnmarshalJSON(data []byte) error {
 	if len(data) == 0 || bytes.Equal(data, []byte(`null`)) {
 		return nil
 	}
-	if data[0] != '[' {
+	switch data[0] {
+	case '[':
+		return DecodeSlice(data, r.columns, &r.rows)
+	case '{':
+		var v tableQueryResultJSON[T]
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		r.columns = v.Columns
+		r.rows = v.Rows
+		if r.rows == nil {
+			r.rows = make([]T, 0)
+		}
+		return nil
+	default:
 		var t T
 		return fmt.Errorf("%T: expected JSON array", t)
 	}
-	return DecodeSlice(data, r.columns, &r.rows)
+}
+
+func (r TableQueryResult[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tableQueryResultJSON[T]{
+		Columns: r.columns,
+		Rows:    r.rows,
+	})
+}
+
+// Columns returns the list of columns requested from the server.
+func (r *TableQueryResult[T]) Columns() []string {
+	return r.columns
+}
+
+// Filter returns a new result containing only rows for which fn
+// returns true.
+func (r *TableQueryResult[T]) Filter(fn func(T) bool) *TableQueryResult[T] {
+	res := NewTableQueryResult[T](r.columns)
+	for _, v := range r.rows {
+		if fn(v) {
+			res.rows = append(res.rows, v)
+		}
+	}
+	return res
 }
 
 func (r *TableQueryResult[T]) Rows() []T {
@@ -326,15 +520,7 @@ func (r *TableQueryResult[T]) Last() (t T) {
 }
 
 func (r *TableQueryResult[T]) Cursor() uint64 {
-	if len(r.rows) == 0 {
-		return 0
-	}
-	tinfo, _ := getTypeInfo(r.rows[0])
-	if !tinfo.Fields[0].ContainsFlag(fieldFlagUint64) {
-		return 0
-	}
-	val := derefValue(reflect.ValueOf(r.Last()))
-	return val.Field(tinfo.Fields[0].Idx[0]).Uint()
+	return List[T](r.rows).Cursor()
 }
 
 // func (c *Client) StreamTable(ctx context.Context, q TableQueryAPI, w io.Writer) (StreamResponse, error) {
@@ -0,0 +1,31 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package client
+
+import (
+	"testing"
+)
+
+func TestTableQueryCheck(t *testing.T) {
+	c := NewClient("http://localhost", nil)
+	tests := []struct {
+		name string
+		q    *TableQuery[exportRow]
+		ok   bool
+	}{
+		{"plain", NewTableQuery[exportRow](c, "test"), true},
+		{"known columns", NewTableQuery[exportRow](c, "test").WithColumns("row_id", "name"), true},
+		{"server columns", NewTableQuery[exportRow](c, "test").WithColumns("row_id", "extra"), true},
+		{"order", NewTableQuery[exportRow](c, "test").OrderBy("name", true), true},
+		{"unknown order", NewTableQuery[exportRow](c, "test").OrderBy("extra", true), false},
+		{"no table", NewTableQuery[exportRow](c, ""), false},
+		{"bad format", NewTableQuery[exportRow](c, "test").WithFormat("xml"), false},
+		{"bad limit", NewTableQuery[exportRow](c, "test").WithLimit(-1), false},
+	}
+	for _, tt := range tests {
+		if err := tt.q.Check(); (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v, want ok=%t", tt.name, err, tt.ok)
+		}
+	}
+}
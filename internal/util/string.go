@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +30,69 @@ func (l *StringList) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, alias(l))
 }
 
+// Contains reports whether s is in the list.
+func (l StringList) Contains(s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsFold reports whether s is in the list using case-insensitive
+// comparison.
+func (l StringList) ContainsFold(s string) bool {
+	for _, v := range l {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort returns a sorted copy of the list.
+func (l StringList) Sort() StringList {
+	res := make(StringList, len(l))
+	copy(res, l)
+	sort.Strings(res)
+	return res
+}
+
+// Unique returns a copy of the list without duplicates. Order of first
+// occurrence is preserved.
+func (l StringList) Unique() StringList {
+	res := make(StringList, 0, len(l))
+	seen := make(map[string]struct{}, len(l))
+	for _, v := range l {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		res = append(res, v)
+	}
+	return res
+}
+
+// Union returns all unique strings contained in l or r.
+func (l StringList) Union(r StringList) StringList {
+	res := make(StringList, 0, len(l)+len(r))
+	res = append(res, l...)
+	res = append(res, r...)
+	return res.Unique()
+}
+
+// Intersect returns all unique strings contained in both l and r.
+func (l StringList) Intersect(r StringList) StringList {
+	res := make(StringList, 0)
+	for _, v := range l.Unique() {
+		if r.Contains(v) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 
 func ToString(t any) string {
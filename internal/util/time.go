@@ -5,6 +5,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"strconv"
 	"time"
 )
@@ -47,3 +48,37 @@ func (f *Time) UnmarshalText(buf []byte) error {
 func (f *Time) UnmarshalJSON(data []byte) error {
 	return f.UnmarshalText(bytes.Trim(data, "\""))
 }
+
+var (
+	// PollInterval defines how often subscriptions and watchers check for
+	// new data.
+	PollInterval = 10 * time.Second
+
+	// MaxBackoff limits the wait time between retries after errors.
+	MaxBackoff = 5 * time.Minute
+)
+
+// Sleep waits for d or until ctx is canceled. It returns false
+// when ctx was canceled.
+func Sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// Backoff doubles d starting from one second up to max.
+func Backoff(d, max time.Duration) time.Duration {
+	if d == 0 {
+		return time.Second
+	}
+	d *= 2
+	if d > max {
+		d = max
+	}
+	return d
+}
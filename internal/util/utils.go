@@ -6,6 +6,7 @@ package util
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"fmt"
 )
 
 func Min(x, y int) int {
@@ -24,8 +25,22 @@ func NonNil(vals ...interface{}) interface{} {
 	return nil
 }
 
+// ParseU64 decodes a hex encoded big-endian uint64 and returns zero
+// on malformed input.
 func ParseU64(s string) (u uint64) {
-	buf, _ := hex.DecodeString(s)
-	u = binary.BigEndian.Uint64(buf[:8])
+	u, _ = ParseU64E(s)
 	return
 }
+
+// ParseU64E decodes a hex encoded big-endian uint64. Input must contain
+// at least 8 bytes (16 hex characters), extra bytes are ignored.
+func ParseU64E(s string) (uint64, error) {
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return 0, fmt.Errorf("parse u64: %w", err)
+	}
+	if len(buf) < 8 {
+		return 0, fmt.Errorf("parse u64: short input of %d bytes", len(buf))
+	}
+	return binary.BigEndian.Uint64(buf[:8]), nil
+}
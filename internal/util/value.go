@@ -4,6 +4,7 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -15,30 +16,71 @@ import (
 
 type ValueWalkerFunc func(path string, value interface{}) error
 
-func WalkValueMap(name string, val interface{}, fn ValueWalkerFunc) error {
+// ErrStopWalk may be returned by a ValueWalkerFunc to stop traversal
+// early. WalkValueMap returns nil in this case.
+var ErrStopWalk = errors.New("stop walk")
+
+// WalkOptions limits traversal of untrusted values. Zero means unlimited.
+type WalkOptions struct {
+	MaxDepth int // max nesting level below the start value
+	MaxNodes int // max number of visited maps, arrays and leaf values
+}
+
+type valueWalker struct {
+	fn    ValueWalkerFunc
+	opts  WalkOptions
+	nodes int
+}
+
+// WalkValueMap calls fn for every leaf value below val. Traversal stops
+// on the first error returned by fn which is passed on to the caller
+// unless it is ErrStopWalk. Optional limits abort traversal with an error
+// when exceeded.
+func WalkValueMap(name string, val interface{}, fn ValueWalkerFunc, opts ...WalkOptions) error {
+	w := &valueWalker{fn: fn}
+	if len(opts) > 0 {
+		w.opts = opts[0]
+	}
+	if err := w.walk(name, val, 0); err != nil && !errors.Is(err, ErrStopWalk) {
+		return err
+	}
+	return nil
+}
+
+func (w *valueWalker) walk(name string, val interface{}, depth int) error {
+	w.nodes++
+	if w.opts.MaxNodes > 0 && w.nodes > w.opts.MaxNodes {
+		return fmt.Errorf("walk %s: max nodes %d exceeded", name, w.opts.MaxNodes)
+	}
 	switch t := val.(type) {
 	case map[string]interface{}:
+		if w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+			return fmt.Errorf("walk %s: max depth %d exceeded", name, w.opts.MaxDepth)
+		}
 		if len(name) > 0 {
 			name += "."
 		}
 		for n, v := range t {
 			child := name + n
-			if err := WalkValueMap(child, v, fn); err != nil {
+			if err := w.walk(child, v, depth+1); err != nil {
 				return err
 			}
 		}
 	case []interface{}:
+		if w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+			return fmt.Errorf("walk %s: max depth %d exceeded", name, w.opts.MaxDepth)
+		}
 		if len(name) > 0 {
 			name += "."
 		}
 		for i, v := range t {
 			child := name + strconv.Itoa(i)
-			if err := WalkValueMap(child, v, fn); err != nil {
+			if err := w.walk(child, v, depth+1); err != nil {
 				return err
 			}
 		}
 	default:
-		return fn(name, val)
+		return w.fn(name, val)
 	}
 	return nil
 }
@@ -152,6 +194,25 @@ func GetPathAddress(val interface{}, path string) (tezos.Address, bool) {
 	return a, err == nil
 }
 
+// GetPathKeyHash parses a base58 key hash (tz1, tz2, tz3, tz4).
+func GetPathKeyHash(val interface{}, path string) (tezos.Address, bool) {
+	a, ok := GetPathAddress(val, path)
+	if !ok || !a.IsEOA() {
+		return tezos.InvalidAddress, false
+	}
+	return a, true
+}
+
+// GetPathKey parses a base58 public key (edpk, sppk, p2pk, BLpk).
+func GetPathKey(val interface{}, path string) (tezos.Key, bool) {
+	str, ok := GetPathString(val, path)
+	if !ok {
+		return tezos.InvalidKey, ok
+	}
+	k, err := tezos.ParseKey(str)
+	return k, err == nil
+}
+
 func GetPathValue(val interface{}, path string) (interface{}, bool) {
 	if tree, ok := val.(map[string]interface{}); ok {
 		frag := strings.Split(path, ".")
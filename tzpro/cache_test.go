@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzpro_test
+
+import (
+	"context"
+	"testing"
+
+	"blockwatch.cc/tzgo/micheline"
+	"blockwatch.cc/tzpro-go/tzpro"
+	"blockwatch.cc/tzpro-go/tzpro/index"
+	"blockwatch.cc/tzpro-go/tzpro/tzprotest"
+)
+
+var cacheTestAddr = tzpro.NewAddress("KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5")
+
+func newCacheTestScript() *index.ContractScript {
+	s := micheline.NewScript()
+	s.Code.Param = micheline.NewCode(micheline.K_PARAMETER, micheline.NewPrim(micheline.T_UNIT))
+	s.Code.Storage = micheline.NewCode(micheline.K_STORAGE, micheline.NewPrim(micheline.T_NAT))
+	s.Code.Code = micheline.NewCode(micheline.K_CODE, micheline.NewSeq(
+		micheline.NewCode(micheline.I_CDR),
+		micheline.NewCode(micheline.I_NIL, micheline.NewPrim(micheline.T_OPERATION)),
+		micheline.NewCode(micheline.I_PAIR),
+	))
+	s.Code.View = micheline.NewSeq(micheline.NewCode(micheline.K_VIEW,
+		micheline.NewString("total"),
+		micheline.NewPrim(micheline.T_UNIT),
+		micheline.NewPrim(micheline.T_NAT),
+		micheline.NewSeq(micheline.NewCode(micheline.I_CDR)),
+	))
+	return &index.ContractScript{Script: s}
+}
+
+func checkCachedCode(t *testing.T, s *index.ContractScript, keepCode bool) {
+	t.Helper()
+	if s == nil || s.Script == nil {
+		t.Fatal("missing script")
+	}
+	if got := s.Script.Code.Code.IsValid(); got != keepCode {
+		t.Errorf("code present %t, want %t", got, keepCode)
+	}
+	if got := len(s.Script.Code.View.Args) > 0; got != keepCode {
+		t.Errorf("view code present %t, want %t", got, keepCode)
+	}
+	if !s.Script.Code.Storage.IsValid() {
+		t.Error("storage type was stripped")
+	}
+	if _, ok := s.Views["total"]; !ok {
+		t.Error("view signatures missing from cached script")
+	}
+}
+
+func TestCachedScriptCode(t *testing.T) {
+	for _, keepCode := range []bool{false, true} {
+		m := tzprotest.NewMockTransport()
+		if err := m.SetResponse("GET", "/explorer/contract/"+cacheTestAddr.String()+"/script", newCacheTestScript()); err != nil {
+			t.Fatal(err)
+		}
+
+		// LoadScript
+		c := m.Client().WithKeepCode(keepCode)
+		s, err := c.Contract.LoadScript(context.Background(), cacheTestAddr)
+		if err != nil {
+			t.Fatalf("keepCode=%t: load: %v", keepCode, err)
+		}
+		checkCachedCode(t, s, keepCode)
+
+		// CacheAdd
+		c = m.Client().WithKeepCode(keepCode)
+		orig := newCacheTestScript()
+		c.CacheAdd(cacheTestAddr, orig)
+		val, ok := c.CacheGet(cacheTestAddr)
+		if !ok {
+			t.Fatalf("keepCode=%t: script not cached", keepCode)
+		}
+		checkCachedCode(t, val.(*index.ContractScript), keepCode)
+		if !orig.Script.Code.Code.IsValid() {
+			t.Errorf("keepCode=%t: CacheAdd modified the caller's script", keepCode)
+		}
+	}
+}
+
+func TestPrefetchScriptsBatchError(t *testing.T) {
+	failed := tzpro.NewAddress("KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn")
+	m := tzprotest.NewMockTransport()
+	if err := m.SetResponse("GET", "/explorer/contract/"+cacheTestAddr.String()+"/script", newCacheTestScript()); err != nil {
+		t.Fatal(err)
+	}
+	m.SetError("GET", "/explorer/contract/"+failed.String()+"/script", 503, nil)
+
+	c := m.Client()
+	err := c.Contract.PrefetchScripts(context.Background(), []tzpro.Address{cacheTestAddr, failed})
+	e, ok := tzpro.IsBatchError(err)
+	if !ok {
+		t.Fatalf("got error %v, want batch error", err)
+	}
+	if _, ok := e.Failed(failed); !ok || e.Len() != 1 {
+		t.Errorf("unexpected failed items: %v", e)
+	}
+	if _, ok := c.CacheGet(cacheTestAddr); !ok {
+		t.Error("successful script was not cached")
+	}
+}
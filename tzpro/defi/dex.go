@@ -14,9 +14,12 @@ import (
 type DexAPI interface {
 	GetDex(context.Context, PoolAddress) (*Dex, error)
 	GetTicker(context.Context, PoolAddress) (*DexTicker, error)
+	GetTickers(context.Context, []PoolAddress) ([]*DexTicker, error)
 	ListPoolEvents(context.Context, PoolAddress, Query) ([]*DexEvent, error)
 	ListPoolTrades(context.Context, PoolAddress, Query) ([]*DexTrade, error)
 	ListPoolPositions(context.Context, PoolAddress, Query) ([]*DexPosition, error)
+	SubscribeTickers(context.Context, ...PoolAddress) (<-chan *DexTicker, <-chan error)
+	GetTokenDexVolume(context.Context, TokenAddress, Query) (*TokenVolume, error)
 
 	// firehose
 	ListDex(context.Context, Query) ([]*Dex, error)
@@ -70,7 +73,21 @@ func (p Dex) Address() PoolAddress {
 	return NewPoolAddress(a, p.PairId)
 }
 
+// Reserves returns the current pool reserves of token A and token B.
+func (p Dex) Reserves() (a, b Z) {
+	return p.SupplyA, p.SupplyB
+}
+
+// HasToken reports whether t is one of the pool's trading tokens.
+func (p Dex) HasToken(t TokenAddress) bool {
+	return (p.TokenA != nil && p.TokenA.Address().Equal(t)) ||
+		(p.TokenB != nil && p.TokenB.Address().Equal(t))
+}
+
 func (c *dexClient) GetDex(ctx context.Context, addr PoolAddress) (*Dex, error) {
+	if !addr.IsValid() {
+		return nil, ErrInvalidAddress
+	}
 	p := &Dex{}
 	u := fmt.Sprintf("/v1/dex/%s", addr)
 	if err := c.client.Get(ctx, u, nil, p); err != nil {
@@ -81,8 +98,8 @@ func (c *dexClient) GetDex(ctx context.Context, addr PoolAddress) (*Dex, error)
 
 func (c *dexClient) ListDex(ctx context.Context, params Query) ([]*Dex, error) {
 	list := make([]*Dex, 0)
-	u := params.WithPath("/v1/dex").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/dex")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -39,17 +39,20 @@ type DexEvent struct {
 
 func (c *dexClient) ListEvents(ctx context.Context, params Query) ([]*DexEvent, error) {
 	list := make([]*DexEvent, 0)
-	u := params.WithPath("/v1/dex/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/dex/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
 func (c *dexClient) ListPoolEvents(ctx context.Context, addr PoolAddress, params Query) ([]*DexEvent, error) {
+	if !addr.IsValid() {
+		return nil, ErrInvalidAddress
+	}
 	list := make([]*DexEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/dex/%s/events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/dex/%s/events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
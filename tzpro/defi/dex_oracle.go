@@ -0,0 +1,165 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+// OraclePageSize is the number of tickers and pools requested per page
+// on refresh.
+var OraclePageSize = 500
+
+type oraclePrice struct {
+	price     float64
+	liquidity float64
+}
+
+// oraclePool holds the trading tokens of a pool.
+type oraclePool struct {
+	a, b *TokenAddress
+}
+
+// PriceOracle caches USD spot prices per token taken from the most
+// liquid pool the token trades in.
+type PriceOracle struct {
+	api      DexAPI
+	interval time.Duration
+	mu       sync.RWMutex
+	prices   map[string]oraclePrice
+	pools    map[PoolAddress]oraclePool
+	updated  time.Time
+}
+
+// NewPriceOracle creates an oracle which Run refreshes every interval.
+// A zero interval polls every 10 seconds.
+func NewPriceOracle(api DexAPI, interval time.Duration) *PriceOracle {
+	if interval <= 0 {
+		interval = util.PollInterval
+	}
+	return &PriceOracle{
+		api:      api,
+		interval: interval,
+		prices:   make(map[string]oraclePrice),
+	}
+}
+
+// PriceUSD returns the cached USD price for token.
+func (o *PriceOracle) PriceUSD(token TokenAddress) (float64, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	p, ok := o.prices[token.String()]
+	return p.price, ok
+}
+
+// Updated returns the time of the last successful refresh.
+func (o *PriceOracle) Updated() time.Time {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.updated
+}
+
+// Refresh loads all DEX tickers and replaces cached prices. Token A of
+// a pool is priced at the ticker's PriceUSD, token B at PriceUSD divided
+// by the ticker's last price. Tickers carry no token addresses, so pool
+// tokens are loaded on first use and reloaded when a ticker refers to a
+// pool not seen before.
+func (o *PriceOracle) Refresh(ctx context.Context) error {
+	tickers := make([]*DexTicker, 0)
+	err := listAll(ctx, client.NewQuery(), OraclePageSize, o.api.ListTickers, func(t *DexTicker) uint64 {
+		tickers = append(tickers, t)
+		return t.Id
+	})
+	if err != nil {
+		return err
+	}
+	o.mu.RLock()
+	pools := o.pools
+	o.mu.RUnlock()
+	for _, t := range tickers {
+		if _, ok := pools[t.Pool]; !ok {
+			pools, err = o.loadPools(ctx)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+	prices := make(map[string]oraclePrice)
+	for _, t := range tickers {
+		p, ok := pools[t.Pool]
+		if !ok || t.PriceUSD == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(t.PriceUSD, 64)
+		if err != nil || price <= 0 {
+			continue
+		}
+		liquidity, _ := strconv.ParseFloat(t.LiquidityUSD, 64)
+		if p.a != nil {
+			setBestPrice(prices, *p.a, price, liquidity)
+		}
+		if p.b != nil && t.LastPrice > 0 {
+			setBestPrice(prices, *p.b, price/t.LastPrice, liquidity)
+		}
+	}
+	o.mu.Lock()
+	o.prices = prices
+	o.pools = pools
+	o.updated = time.Now()
+	o.mu.Unlock()
+	return nil
+}
+
+// loadPools lists all pools and returns their trading tokens.
+func (o *PriceOracle) loadPools(ctx context.Context) (map[PoolAddress]oraclePool, error) {
+	pools := make(map[PoolAddress]oraclePool)
+	err := listAll(ctx, client.NewQuery(), OraclePageSize, o.api.ListDex, func(d *Dex) uint64 {
+		var p oraclePool
+		if d.TokenA != nil {
+			a := d.TokenA.Address()
+			p.a = &a
+		}
+		if d.TokenB != nil {
+			b := d.TokenB.Address()
+			p.b = &b
+		}
+		pools[d.Address()] = p
+		return d.Id
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+func setBestPrice(m map[string]oraclePrice, t TokenAddress, price, liquidity float64) {
+	key := t.String()
+	if p, ok := m[key]; ok && p.liquidity >= liquidity {
+		return
+	}
+	m[key] = oraclePrice{price: price, liquidity: liquidity}
+}
+
+// Run refreshes prices every interval until ctx is canceled. Refresh
+// errors are sent to errs when non-nil and do not stop the loop.
+func (o *PriceOracle) Run(ctx context.Context, errs chan<- error) {
+	for {
+		if err := o.Refresh(ctx); err != nil && errs != nil {
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+		if !util.Sleep(ctx, o.interval) {
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blockwatch.cc/tzgo/tezos"
+	"blockwatch.cc/tzpro-go/internal/client"
+)
+
+func TestPriceOracleRefresh(t *testing.T) {
+	const (
+		pools = `[{
+			"id": 1,
+			"contract": "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5",
+			"pair_id": 0,
+			"token_a": {"contract": "KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn", "token_id": "0"},
+			"token_b": {"contract": "KT1XnTn74bUtxHfDtBmm2bGZAQfhPbvKWR8o", "token_id": "0"}
+		}]`
+		tickers = `[{
+			"id": 38,
+			"pool": "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5_0",
+			"last_price": "4",
+			"liquidity_usd": "1000",
+			"price_usd": "2"
+		}]`
+	)
+	var nPools int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/dex":
+			nPools++
+			w.Write([]byte(pools))
+		case "/v1/dex/tickers":
+			w.Write([]byte(tickers))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	o := NewPriceOracle(NewDexAPI(client.NewClient(srv.URL, nil)), 0)
+	for i := 0; i < 2; i++ {
+		if err := o.Refresh(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if nPools != 1 {
+		t.Errorf("pools loaded %d times, want 1", nPools)
+	}
+	tests := []struct {
+		token string
+		price float64
+	}{
+		{"KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn", 2},
+		{"KT1XnTn74bUtxHfDtBmm2bGZAQfhPbvKWR8o", 0.5},
+	}
+	for _, tt := range tests {
+		token := tezos.NewToken(tezos.MustParseAddress(tt.token), tezos.NewZ(0))
+		price, ok := o.PriceUSD(token)
+		if !ok || price != tt.price {
+			t.Errorf("%s: got price %f %t, want %f", tt.token, price, ok, tt.price)
+		}
+	}
+}
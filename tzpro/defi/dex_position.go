@@ -49,17 +49,20 @@ type DexPosition struct {
 
 func (c *dexClient) ListPositions(ctx context.Context, params Query) ([]*DexPosition, error) {
 	list := make([]*DexPosition, 0)
-	u := params.WithPath("/v1/dex/positions").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/dex/positions")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
 func (c *dexClient) ListPoolPositions(ctx context.Context, addr PoolAddress, params Query) ([]*DexPosition, error) {
+	if !addr.IsValid() {
+		return nil, ErrInvalidAddress
+	}
 	list := make([]*DexPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/dex/%s/positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/dex/%s/positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
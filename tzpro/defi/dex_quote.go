@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Quote estimates the output of swapping amountIn of tokenIn against a
+// constant-product pool after deducting the pool's fee. Price impact is
+// returned in basispoints between the pool's mid price before and after
+// the swap, matching DexTrade.Impact.
+func Quote(pool *Dex, amountIn Z, tokenIn TokenAddress) (amountOut Z, priceImpact float64, err error) {
+	if pool == nil {
+		err = fmt.Errorf("quote: nil pool")
+		return
+	}
+	if amountIn.IsNeg() || amountIn.IsZero() {
+		err = fmt.Errorf("quote: amount must be positive")
+		return
+	}
+	reserveIn, reserveOut := pool.Reserves()
+	switch {
+	case pool.TokenA != nil && pool.TokenA.Address().Equal(tokenIn):
+	case pool.TokenB != nil && pool.TokenB.Address().Equal(tokenIn):
+		reserveIn, reserveOut = reserveOut, reserveIn
+	default:
+		err = fmt.Errorf("quote: token %s not traded in pool %s", tokenIn, pool.Address())
+		return
+	}
+	if reserveIn.IsZero() || reserveOut.IsZero() {
+		err = fmt.Errorf("quote: pool %s has no liquidity", pool.Address())
+		return
+	}
+
+	// fee is applied to the input amount with 1e-6 bps precision
+	const scale = 10000 * 1000000
+	feeFactor := int64(math.Round((10000 - pool.FeesBps) * 1000000))
+	if feeFactor <= 0 || feeFactor > scale {
+		err = fmt.Errorf("quote: invalid pool fee %f bps", pool.FeesBps)
+		return
+	}
+	inWithFee := amountIn.Mul64(feeFactor)
+	amountOut = reserveOut.Mul(inWithFee).Div(reserveIn.Mul64(scale).Add(inWithFee))
+
+	// mid price is reserveOut / reserveIn, compare before and after
+	before := new(big.Float).Quo(
+		new(big.Float).SetInt(reserveOut.Big()),
+		new(big.Float).SetInt(reserveIn.Big()),
+	)
+	after := new(big.Float).Quo(
+		new(big.Float).SetInt(reserveOut.Sub(amountOut).Big()),
+		new(big.Float).SetInt(reserveIn.Add(amountIn).Big()),
+	)
+	ratio, _ := new(big.Float).Quo(after, before).Float64()
+	priceImpact = (1 - ratio) * 10000
+	return
+}
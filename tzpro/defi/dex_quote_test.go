@@ -0,0 +1,71 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"math"
+	"testing"
+
+	"blockwatch.cc/tzgo/tezos"
+)
+
+func TestQuote(t *testing.T) {
+	var (
+		tokenA = tezos.NewToken(tezos.MustParseAddress("KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn"), tezos.NewZ(0))
+		tokenB = tezos.NewToken(tezos.MustParseAddress("KT1XnTn74bUtxHfDtBmm2bGZAQfhPbvKWR8o"), tezos.NewZ(0))
+		tokenC = tezos.NewToken(tezos.MustParseAddress("KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5"), tezos.NewZ(0))
+	)
+	pool := func(a, b int64, fee float64) *Dex {
+		return &Dex{
+			Contract: "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5",
+			TokenA:   &Token{Contract: tokenA.Contract(), TokenId: tokenA.TokenId()},
+			TokenB:   &Token{Contract: tokenB.Contract(), TokenId: tokenB.TokenId()},
+			SupplyA:  tezos.NewZ(a),
+			SupplyB:  tezos.NewZ(b),
+			FeesBps:  fee,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		pool   *Dex
+		in     int64
+		token  TokenAddress
+		out    int64
+		impact float64
+		err    bool
+	}{
+		{"a to b", pool(1000000, 2000000, 0), 10000, tokenA, 19801, 197.034653, false},
+		{"a to b with fee", pool(1000000, 2000000, 30), 10000, tokenA, 19743, 196.747525, false},
+		{"b to a", pool(1000000, 2000000, 0), 20000, tokenB, 9900, 197.029703, false},
+		{"rounds down", pool(1000, 1000, 0), 100, tokenA, 90, 1727.272727, false},
+		{"nil pool", nil, 100, tokenA, 0, 0, true},
+		{"zero amount", pool(1000, 1000, 0), 0, tokenA, 0, 0, true},
+		{"negative amount", pool(1000, 1000, 0), -1, tokenA, 0, 0, true},
+		{"unknown token", pool(1000, 1000, 0), 100, tokenC, 0, 0, true},
+		{"no liquidity", pool(0, 1000, 0), 100, tokenA, 0, 0, true},
+		{"full fee", pool(1000, 1000, 10000), 100, tokenA, 0, 0, true},
+		{"negative fee", pool(1000, 1000, -1), 100, tokenA, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, impact, err := Quote(tt.pool, tezos.NewZ(tt.in), tt.token)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error, got %s", out)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Int64() != tt.out {
+				t.Errorf("got amount %s, want %d", out, tt.out)
+			}
+			if math.Abs(impact-tt.impact) > 1e-6 {
+				t.Errorf("got impact %f, want %f", impact, tt.impact)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"context"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+// SubscribeTickers streams ticker updates for one or more pools from a
+// single loop which polls every 10 seconds. The latest snapshot of each
+// pool is sent first and again after recovering from errors; afterwards
+// only changed tickers are sent. Errors are reported on the error channel without ending the
+// subscription. Both channels are closed when ctx is canceled.
+func (c *dexClient) SubscribeTickers(ctx context.Context, pools ...PoolAddress) (<-chan *DexTicker, <-chan error) {
+	ticks := make(chan *DexTicker)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(ticks)
+		var (
+			last    = make(map[PoolAddress]*DexTicker, len(pools))
+			backoff time.Duration
+		)
+		for {
+			var failed error
+			for _, pool := range pools {
+				tick, err := c.GetTicker(ctx, pool)
+				if err != nil {
+					failed = err
+					break
+				}
+				if prev, ok := last[pool]; ok && sameTicker(prev, tick) {
+					continue
+				}
+				last[pool] = tick
+				select {
+				case ticks <- tick:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if failed != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case errs <- failed:
+				default:
+				}
+				wait := util.Backoff(backoff, util.MaxBackoff)
+				if e, ok := client.IsErrRateLimited(failed); ok && e.Deadline() > wait {
+					wait = e.Deadline()
+				}
+				backoff = wait
+				// resend snapshots after recovery
+				last = make(map[PoolAddress]*DexTicker, len(pools))
+				if !util.Sleep(ctx, wait) {
+					return
+				}
+				continue
+			}
+			backoff = 0
+			if !util.Sleep(ctx, util.PollInterval) {
+				return
+			}
+		}
+	}()
+	return ticks, errs
+}
+
+// sameTicker reports whether prices, volumes and the last trade of two
+// tickers are equal.
+func sameTicker(a, b *DexTicker) bool {
+	return a.LastPrice == b.LastPrice &&
+		a.AskPrice == b.AskPrice &&
+		a.LastQty == b.LastQty &&
+		a.LastTradeTime.Equal(b.LastTradeTime) &&
+		a.BaseVolume == b.BaseVolume &&
+		a.QuoteVolume == b.QuoteVolume &&
+		a.NumTrades == b.NumTrades &&
+		a.LiquidityUSD == b.LiquidityUSD &&
+		a.PriceUSD == b.PriceUSD
+}
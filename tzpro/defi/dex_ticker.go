@@ -6,7 +6,13 @@ package defi
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
 )
 
 type DexTicker struct {
@@ -34,7 +40,63 @@ type DexTicker struct {
 	PriceUSD         string      `json:"price_usd"`
 }
 
+// PriceChangePercent returns the 24h price change in percent derived
+// from PriceChangeBps. Returns an error when the server sent no valid
+// change so callers can tell unknown from flat.
+func (d *DexTicker) PriceChangePercent() (float64, error) {
+	if math.IsNaN(d.PriceChangeBps) || math.IsInf(d.PriceChangeBps, 0) {
+		return 0, fmt.Errorf("ticker %s: invalid price change", d.Pair)
+	}
+	return d.PriceChangeBps / 100, nil
+}
+
+// IsUp reports whether the price increased over the ticker period.
+func (d *DexTicker) IsUp() bool {
+	return d.PriceChangeBps > 0 || (d.PriceChangeBps == 0 && d.PriceChange > 0)
+}
+
+// LastTrade returns the time of the most recent trade. It fails when the
+// pool has not traded yet.
+func (d *DexTicker) LastTrade() (time.Time, error) {
+	if d.LastTradeTime.IsZero() {
+		return time.Time{}, fmt.Errorf("ticker %s: no trades", d.Pair)
+	}
+	return d.LastTradeTime, nil
+}
+
+// UpdatedAt returns the time of the last trade or, when the pool did not
+// trade, the close time of the ticker period.
+func (d *DexTicker) UpdatedAt() time.Time {
+	if !d.LastTradeTime.IsZero() {
+		return d.LastTradeTime
+	}
+	return d.CloseTime
+}
+
+// IsStale reports whether the ticker was last updated more than maxAge
+// ago. Tickers without any timestamp are always stale.
+func (d *DexTicker) IsStale(maxAge time.Duration) bool {
+	t := d.UpdatedAt()
+	return t.IsZero() || time.Since(t) > maxAge
+}
+
+// BaseQuote splits Pair into base and quote symbols. Pairs may use '/',
+// '_' or '-' as separator. Returns ok=false unless Pair contains exactly
+// one separator between two non-empty symbols.
+func (d *DexTicker) BaseQuote() (base, quote string, ok bool) {
+	parts := strings.FieldsFunc(d.Pair, func(r rune) bool {
+		return r == '/' || r == '_' || r == '-'
+	})
+	if len(parts) != 2 || len(parts[0])+len(parts[1])+1 != len(d.Pair) {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
 func (c *dexClient) GetTicker(ctx context.Context, addr PoolAddress) (*DexTicker, error) {
+	if !addr.IsValid() {
+		return nil, ErrInvalidAddress
+	}
 	tick := &DexTicker{}
 	u := fmt.Sprintf("/v1/dex/%s/ticker", addr)
 	if err := c.client.Get(ctx, u, nil, tick); err != nil {
@@ -43,11 +105,208 @@ func (c *dexClient) GetTicker(ctx context.Context, addr PoolAddress) (*DexTicker
 	return tick, nil
 }
 
+// TickerConcurrency limits the number of tickers fetched in parallel by
+// GetTickers.
+var TickerConcurrency = 8
+
+// GetTickers fetches tickers for pools in parallel. Results are in the
+// order of pools. Tickers of pools that failed are nil and their errors
+// are returned as *BatchError keyed by pool. A canceled context fails the
+// whole batch.
+func (c *dexClient) GetTickers(ctx context.Context, pools []PoolAddress) ([]*DexTicker, error) {
+	workers := TickerConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	var (
+		res  = make([]*DexTicker, len(pools))
+		mu   sync.Mutex
+		errs = client.NewBatchError[PoolAddress]()
+		next = make(chan int)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < workers && i < len(pools); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range next {
+				tick, err := c.GetTicker(ctx, pools[n])
+				if err != nil {
+					mu.Lock()
+					errs.Add(pools[n], err)
+					mu.Unlock()
+					continue
+				}
+				res[n] = tick
+			}
+		}()
+	}
+	for i := range pools {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(next)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return res, errs.ErrorOrNil()
+}
+
+// ToUSD converts an amount of the pair's base token with decimals into
+// USD using PriceUSD. Returns an error when the ticker carries no price.
+func (d *DexTicker) ToUSD(amount Z, decimals int) (float64, error) {
+	if d.PriceUSD == "" {
+		return 0, fmt.Errorf("ticker %s: no USD price", d.Pair)
+	}
+	price, err := strconv.ParseFloat(d.PriceUSD, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ticker %s: invalid USD price %q: %w", d.Pair, d.PriceUSD, err)
+	}
+	if price == 0 || amount.IsZero() {
+		return 0, nil
+	}
+	return amount.Float64(decimals) * price, nil
+}
+
+// DexSortField is a ticker field the server can sort by.
+type DexSortField string
+
+const (
+	DexSortVolume      DexSortField = "quote_volume"
+	DexSortLiquidity   DexSortField = "liquidity_usd"
+	DexSortPriceChange DexSortField = "price_change_bps"
+	DexSortNumTrades   DexSortField = "num_trades"
+)
+
+func (f DexSortField) IsValid() bool {
+	switch f {
+	case DexSortVolume, DexSortLiquidity, DexSortPriceChange, DexSortNumTrades:
+		return true
+	default:
+		return false
+	}
+}
+
+// ListTickers returns tickers for all pools. Use Query.WithSort with a
+// DexSortField and Query.WithLimit to fetch top pairs.
 func (c *dexClient) ListTickers(ctx context.Context, params Query) ([]*DexTicker, error) {
+	if f := DexSortField(params.Query.Get("order_by")); f != "" && !f.IsValid() {
+		return nil, fmt.Errorf("tickers cannot be sorted by %q", f)
+	}
 	list := make([]*DexTicker, 0)
-	u := params.WithPath("/v1/dex/tickers").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/dex/tickers")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
+
+// TokenVolume is the trading volume of a token summed across all DEX
+// pools that trade it.
+type TokenVolume struct {
+	Token        TokenAddress
+	Volume       float64   // traded amount in token units
+	LiquidityUSD float64   // USD liquidity of all pools including the pair token
+	NumTrades    int       // trades across all pools
+	NumPools     int       // pools trading the token
+	OpenTime     time.Time // earliest start of the ticker windows
+	CloseTime    time.Time // latest end of the ticker windows
+}
+
+// GetTokenDexVolume aggregates tickers of all pools which trade token.
+// Volume is taken from the base side when token is the pool's token A and
+// from the quote side otherwise. Tickers cover a rolling 24h window, so the
+// result spans OpenTime to CloseTime of the aggregated tickers. Params
+// filter the pool listing only, tickers are matched to the selected pools
+// on the client. Both listings are read page by page with cursors until
+// exhausted, pools using the limit in params as page size or
+// VolumePageSize when unset.
+func (c *dexClient) GetTokenDexVolume(ctx context.Context, token TokenAddress, params Query) (*TokenVolume, error) {
+	limit := VolumePageSize
+	if n, err := strconv.Atoi(params.Query.Get("limit")); err == nil && n > 0 {
+		limit = n
+	}
+	isBase := make(map[PoolAddress]bool)
+	err := listAll(ctx, params, limit, c.ListDex, func(p *Dex) uint64 {
+		if p.HasToken(token) {
+			isBase[p.Address()] = p.TokenA != nil && p.TokenA.Address().Equal(token)
+		}
+		return p.Id
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &TokenVolume{Token: token}
+	if len(isBase) == 0 {
+		return res, nil
+	}
+	tickers := make([]*DexTicker, 0, len(isBase))
+	err = listAll(ctx, client.NewQuery(), VolumePageSize, c.ListTickers, func(t *DexTicker) uint64 {
+		if _, ok := isBase[t.Pool]; ok {
+			tickers = append(tickers, t)
+		}
+		return t.Id
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tickers {
+		base, ok := isBase[t.Pool]
+		if !ok {
+			continue
+		}
+		if base {
+			res.Volume += t.BaseVolume
+		} else {
+			res.Volume += t.QuoteVolume
+		}
+		if t.LiquidityUSD != "" {
+			liq, err := strconv.ParseFloat(t.LiquidityUSD, 64)
+			if err != nil {
+				return nil, fmt.Errorf("ticker %s: invalid USD liquidity %q: %w", t.Pair, t.LiquidityUSD, err)
+			}
+			res.LiquidityUSD += liq
+		}
+		res.NumTrades += t.NumTrades
+		res.NumPools++
+		if res.OpenTime.IsZero() || t.OpenTime.Before(res.OpenTime) {
+			res.OpenTime = t.OpenTime
+		}
+		if t.CloseTime.After(res.CloseTime) {
+			res.CloseTime = t.CloseTime
+		}
+	}
+	return res, nil
+}
+
+// VolumePageSize is the number of pools and tickers requested per page by
+// GetTokenDexVolume.
+var VolumePageSize = 500
+
+// listAll calls list with cursor paging in pages of limit rows until a
+// short page is returned and passes each row to fn which returns the row
+// id.
+func listAll[T any](ctx context.Context, params Query, limit int, list func(context.Context, Query) ([]T, error), fn func(T) uint64) error {
+	if limit > int(client.MaxLimit) {
+		limit = int(client.MaxLimit)
+	}
+	var cursor uint64
+	for {
+		page, err := list(ctx, params.Clone().WithLimit(uint(limit)).WithCursor(cursor))
+		if err != nil {
+			return err
+		}
+		for _, v := range page {
+			cursor = fn(v)
+		}
+		if len(page) < limit {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blockwatch.cc/tzgo/tezos"
+	"blockwatch.cc/tzpro-go/internal/client"
+)
+
+const tickerPayload = `{
+  "id": 38,
+  "pair": "tzBTC/XTZ",
+  "pool": "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5_0",
+  "name": "Quipuswap tzBTC/XTZ",
+  "entity": "Quipuswap",
+  "price_change": "-512.731054",
+  "price_change_bps": "-182",
+  "ask_price": "27638.451227",
+  "weighted_avg_price": "27811.040811",
+  "last_price": "27638.451227",
+  "last_qty": "0.00321",
+  "last_trade_time": "2023-05-09T14:23:41Z",
+  "base_volume": "1.82904351",
+  "quote_volume": "50862.417551",
+  "open_price": "28151.182281",
+  "high_price": "28201.99012",
+  "low_price": "27502.612113",
+  "open_time": "2023-05-08T14:24:00Z",
+  "close_time": "2023-05-09T14:24:00Z",
+  "num_trades": 121,
+  "liquidity_usd": "2214523.33",
+  "price_usd": "27618.99"
+}`
+
+func TestDexTickerLastTrade(t *testing.T) {
+	var tick DexTicker
+	if err := json.Unmarshal([]byte(tickerPayload), &tick); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	got, err := tick.LastTrade()
+	if err != nil {
+		t.Fatalf("last trade: %v", err)
+	}
+	want := time.Date(2023, 5, 9, 14, 23, 41, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("last trade %s, want %s", got, want)
+	}
+	if !tick.UpdatedAt().Equal(want) {
+		t.Errorf("updated at %s, want %s", tick.UpdatedAt(), want)
+	}
+	if tick.Pool.String() != "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5_0" {
+		t.Errorf("pool %s", tick.Pool)
+	}
+	if tick.LastPrice != 27638.451227 || tick.NumTrades != 121 {
+		t.Errorf("unexpected price %f or trades %d", tick.LastPrice, tick.NumTrades)
+	}
+}
+
+func TestDexTickerNoTrades(t *testing.T) {
+	for _, v := range []string{`null`, `"0001-01-01T00:00:00Z"`} {
+		var tick DexTicker
+		buf := []byte(`{"pair":"A/B","last_trade_time":` + v + `,"close_time":"2023-05-09T14:24:00Z"}`)
+		if err := json.Unmarshal(buf, &tick); err != nil {
+			t.Fatalf("%s: decode: %v", v, err)
+		}
+		if _, err := tick.LastTrade(); err == nil {
+			t.Errorf("%s: expected error for pool without trades", v)
+		}
+		if want := time.Date(2023, 5, 9, 14, 24, 0, 0, time.UTC); !tick.UpdatedAt().Equal(want) {
+			t.Errorf("%s: updated at %s, want close time", v, tick.UpdatedAt())
+		}
+	}
+}
+
+func TestGetTickersBatchError(t *testing.T) {
+	good, _ := ParsePoolAddress("KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5_0")
+	bad, _ := ParsePoolAddress("KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5_1")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, bad.String()) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":400,"message":"invalid pool"}`))
+			return
+		}
+		w.Write([]byte(tickerPayload))
+	}))
+	defer srv.Close()
+
+	api := NewDexAPI(client.NewClient(srv.URL, nil).WithRetry(0, 0))
+	ticks, err := api.GetTickers(context.Background(), []PoolAddress{good, bad})
+	e, ok := IsBatchError(err)
+	if !ok {
+		t.Fatalf("got error %v, want batch error", err)
+	}
+	if _, ok := e.Failed(bad); !ok || e.Len() != 1 {
+		t.Errorf("unexpected failed pools: %v", e)
+	}
+	if len(ticks) != 2 || ticks[0] == nil || ticks[1] != nil {
+		t.Errorf("unexpected tickers %v", ticks)
+	}
+}
+
+func TestGetTokenDexVolumeQueries(t *testing.T) {
+	const pools = `[{
+		"id": 1,
+		"contract": "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5",
+		"pair_id": 0,
+		"token_a": {"contract": "KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn", "token_id": "0"}
+	}]`
+	var tickerQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/dex":
+			w.Write([]byte(pools))
+		case "/v1/dex/tickers":
+			tickerQuery = r.URL.RawQuery
+			w.Write([]byte("[" + tickerPayload + "]"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	token := tezos.NewToken(tezos.MustParseAddress("KT1PWx2mnDueood7fEmfbBDKx1D9BAnnXitn"), tezos.NewZ(0))
+	api := NewDexAPI(client.NewClient(srv.URL, nil))
+	params := client.NewQuery().WithSort("first_time", true)
+	vol, err := api.GetTokenDexVolume(context.Background(), token, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vol.NumPools != 1 || vol.NumTrades != 121 {
+		t.Errorf("unexpected volume %+v", vol)
+	}
+	if strings.Contains(tickerQuery, "order_by") {
+		t.Errorf("pool params were sent to tickers: %s", tickerQuery)
+	}
+}
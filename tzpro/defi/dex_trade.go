@@ -68,19 +68,28 @@ type DexTrade struct {
 	VolumeUSD      float64   `json:"volume_usd,string"`
 }
 
+func (t DexTrade) Address() PoolAddress {
+	return NewPoolAddress(t.Contract, t.PairId)
+}
+
 func (c *dexClient) ListTrades(ctx context.Context, params Query) ([]*DexTrade, error) {
 	list := make([]*DexTrade, 0)
-	u := params.WithPath("/v1/dex/trades").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/dex/trades")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
+// ListPoolTrades returns individual swaps executed in a pool. Use the last
+// trade's Id with Query.WithCursor to fetch the next page.
 func (c *dexClient) ListPoolTrades(ctx context.Context, addr PoolAddress, params Query) ([]*DexTrade, error) {
+	if !addr.IsValid() {
+		return nil, ErrInvalidAddress
+	}
 	list := make([]*DexTrade, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/dex/%s/trades", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/dex/%s/trades", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -0,0 +1,168 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package defi
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+// PriceAlertKind identifies the condition that triggered a PriceAlert.
+type PriceAlertKind string
+
+const (
+	PriceAlertAbove PriceAlertKind = "above"
+	PriceAlertBelow PriceAlertKind = "below"
+	PriceAlertMove  PriceAlertKind = "move"
+)
+
+// PriceRule defines price thresholds for a pool. Zero values disable the
+// respective check. Prices are compared against DexTicker.LastPrice.
+type PriceRule struct {
+	Pool    PoolAddress
+	Above   float64 // alert when the price crosses above
+	Below   float64 // alert when the price crosses below
+	MovePct float64 // alert when the price moved by at least this percentage
+}
+
+// PriceAlert is sent when a ticker crosses a rule threshold. Ref is the
+// threshold for above and below alerts and the reference price for moves.
+type PriceAlert struct {
+	Kind   PriceAlertKind
+	Rule   PriceRule
+	Price  float64
+	Ref    float64
+	Ticker *DexTicker
+}
+
+// priceState tracks the last observed side of each threshold so that an
+// alert fires once per crossing.
+type priceState struct {
+	init  bool
+	above bool    // price was above Rule.Above
+	below bool    // price was below Rule.Below
+	ref   float64 // reference price for move alerts
+}
+
+// PriceWatcher polls tickers of all watched pools and calls a handler when
+// a price crosses a threshold. Alerts fire once per crossing and re-arm
+// when the price returns. Move alerts compare against the price at the
+// first poll and after each move alert against the alerting price.
+type PriceWatcher struct {
+	api      DexAPI
+	interval time.Duration
+	rules    []PriceRule
+	onAlert  func(PriceAlert)
+	onError  func(error)
+}
+
+// NewPriceWatcher creates a watcher which polls api every interval and
+// calls fn for each alert. A zero interval polls every 10 seconds.
+func NewPriceWatcher(api DexAPI, interval time.Duration, fn func(PriceAlert)) *PriceWatcher {
+	if interval <= 0 {
+		interval = util.PollInterval
+	}
+	return &PriceWatcher{
+		api:      api,
+		interval: interval,
+		onAlert:  fn,
+	}
+}
+
+// Watch adds rule. Rules must be added before Run is called.
+func (w *PriceWatcher) Watch(rule PriceRule) *PriceWatcher {
+	w.rules = append(w.rules, rule)
+	return w
+}
+
+// OnError sets a handler for polling errors. Polling continues with
+// exponential backoff up to 5 minutes after errors.
+func (w *PriceWatcher) OnError(fn func(error)) *PriceWatcher {
+	w.onError = fn
+	return w
+}
+
+// Run polls until ctx is canceled and returns the context error.
+func (w *PriceWatcher) Run(ctx context.Context) error {
+	var (
+		pools   = make([]PoolAddress, 0, len(w.rules))
+		seen    = make(map[PoolAddress]bool)
+		state   = make([]priceState, len(w.rules))
+		backoff time.Duration
+	)
+	for _, r := range w.rules {
+		if !seen[r.Pool] {
+			seen[r.Pool] = true
+			pools = append(pools, r.Pool)
+		}
+	}
+	for {
+		ticks, err := w.api.GetTickers(ctx, pools)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		byPool := make(map[PoolAddress]*DexTicker, len(ticks))
+		for _, t := range ticks {
+			if t != nil {
+				byPool[t.Pool] = t
+			}
+		}
+		for i, r := range w.rules {
+			if t, ok := byPool[r.Pool]; ok {
+				w.check(r, &state[i], t)
+			}
+		}
+		wait := w.interval
+		if err != nil {
+			if w.onError != nil {
+				w.onError(err)
+			}
+			backoff = util.Backoff(backoff, util.MaxBackoff)
+			if e, ok := client.IsErrRateLimited(err); ok && e.Deadline() > backoff {
+				backoff = e.Deadline()
+			}
+			if backoff > wait {
+				wait = backoff
+			}
+		} else {
+			backoff = 0
+		}
+		if !util.Sleep(ctx, wait) {
+			return ctx.Err()
+		}
+	}
+}
+
+func (w *PriceWatcher) check(r PriceRule, s *priceState, t *DexTicker) {
+	p := t.LastPrice
+	if math.IsNaN(p) || math.IsInf(p, 0) || p <= 0 {
+		return
+	}
+	above, below := r.Above > 0 && p > r.Above, r.Below > 0 && p < r.Below
+	if !s.init {
+		s.init, s.above, s.below, s.ref = true, above, below, p
+		return
+	}
+	if above && !s.above {
+		w.fire(PriceAlert{Kind: PriceAlertAbove, Rule: r, Price: p, Ref: r.Above, Ticker: t})
+	}
+	if below && !s.below {
+		w.fire(PriceAlert{Kind: PriceAlertBelow, Rule: r, Price: p, Ref: r.Below, Ticker: t})
+	}
+	s.above, s.below = above, below
+	if r.MovePct > 0 && math.Abs(p-s.ref)/s.ref*100 >= r.MovePct {
+		w.fire(PriceAlert{Kind: PriceAlertMove, Rule: r, Price: p, Ref: s.ref, Ticker: t})
+		s.ref = p
+	}
+}
+
+func (w *PriceWatcher) fire(a PriceAlert) {
+	if w.onAlert != nil {
+		w.onAlert(a)
+	}
+}
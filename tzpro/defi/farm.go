@@ -66,8 +66,8 @@ func (c *farmClient) GetFarm(ctx context.Context, addr PoolAddress) (*Farm, erro
 
 func (c *farmClient) ListFarms(ctx context.Context, params Query) ([]*Farm, error) {
 	list := make([]*Farm, 0)
-	u := params.WithPath("/v1/farm").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/farm")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -40,8 +40,8 @@ type FarmEvent struct {
 
 func (c *farmClient) ListEvents(ctx context.Context, params Query) ([]*FarmEvent, error) {
 	list := make([]*FarmEvent, 0)
-	u := params.WithPath("/v1/farm/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/farm/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -49,8 +49,8 @@ func (c *farmClient) ListEvents(ctx context.Context, params Query) ([]*FarmEvent
 
 func (c *farmClient) ListPoolEvents(ctx context.Context, addr PoolAddress, params Query) ([]*FarmEvent, error) {
 	list := make([]*FarmEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/farm/%s/events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/farm/%s/events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
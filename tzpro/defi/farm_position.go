@@ -41,8 +41,8 @@ type FarmPosition struct {
 
 func (c *farmClient) ListPositions(ctx context.Context, params Query) ([]*FarmPosition, error) {
 	list := make([]*FarmPosition, 0)
-	u := params.WithPath("/v1/farm/positions").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/farm/positions")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -50,8 +50,8 @@ func (c *farmClient) ListPositions(ctx context.Context, params Query) ([]*FarmPo
 
 func (c *farmClient) ListFarmPoolPositions(ctx context.Context, addr PoolAddress, params Query) ([]*FarmPosition, error) {
 	list := make([]*FarmPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/farm/%s/positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/farm/%s/positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -65,8 +65,8 @@ func (c *lendClient) GetPool(ctx context.Context, addr PoolAddress) (*LendingPoo
 
 func (c *lendClient) ListPools(ctx context.Context, params Query) ([]*LendingPool, error) {
 	list := make([]*LendingPool, 0)
-	u := params.WithPath("/v1/lend").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/lend")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
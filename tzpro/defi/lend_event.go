@@ -40,8 +40,8 @@ type LendingEvent struct {
 
 func (c *lendClient) ListEvents(ctx context.Context, params Query) ([]*LendingEvent, error) {
 	list := make([]*LendingEvent, 0)
-	u := params.WithPath("/v1/lend/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/lend/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -49,8 +49,8 @@ func (c *lendClient) ListEvents(ctx context.Context, params Query) ([]*LendingEv
 
 func (c *lendClient) ListPoolEvents(ctx context.Context, addr PoolAddress, params Query) ([]*LendingEvent, error) {
 	list := make([]*LendingEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/lend/%s/events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/lend/%s/events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
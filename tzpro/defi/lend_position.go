@@ -45,8 +45,8 @@ type LendingPosition struct {
 
 func (c *lendClient) ListPositions(ctx context.Context, params Query) ([]*LendingPosition, error) {
 	list := make([]*LendingPosition, 0)
-	u := params.WithPath("/v1/lend/positions").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/lend/positions")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -54,8 +54,8 @@ func (c *lendClient) ListPositions(ctx context.Context, params Query) ([]*Lendin
 
 func (c *lendClient) ListPoolPositions(ctx context.Context, addr PoolAddress, params Query) ([]*LendingPosition, error) {
 	list := make([]*LendingPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/lend/%s/positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/lend/%s/positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -46,6 +46,11 @@ func MustParsePoolAddress(s string) PoolAddress {
 	return a
 }
 
+// IsValid reports whether a refers to a non-zero contract and pool id.
+func (a PoolAddress) IsValid() bool {
+	return a.Hash != [20]byte{} && a.Id >= 0
+}
+
 func (a PoolAddress) Contract() Address {
 	return NewAddress(AddressTypeContract, a.Hash[:])
 }
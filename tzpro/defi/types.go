@@ -18,10 +18,14 @@ type (
 
 	Token        = token.Token
 	TokenAddress = tezos.Token
+
+	BatchError = client.BatchError[PoolAddress]
 )
 
 var (
 	AddressTypeContract = tezos.AddressTypeContract
 	ParseAddress        = tezos.ParseAddress
 	NewAddress          = tezos.NewAddress
+	ErrInvalidAddress   = client.ErrInvalidAddress
+	IsBatchError        = client.IsBatchError[PoolAddress]
 )
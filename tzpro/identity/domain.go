@@ -73,8 +73,8 @@ func (c *domainClient) LookupByAddress(ctx context.Context, addr Address) (*Doma
 
 func (c *domainClient) ListDomains(ctx context.Context, params Query) ([]*Domain, error) {
 	list := make([]*Domain, 0)
-	u := params.WithPath("/v1/domains").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/domains")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -30,8 +30,8 @@ type DomainEvent struct {
 
 func (c *domainClient) ListEvents(ctx context.Context, params Query) ([]*DomainEvent, error) {
 	list := make([]*DomainEvent, 0)
-	u := params.WithPath("/v1/domains/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/domains/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -54,8 +54,8 @@ type Profile struct {
 
 func (c *profileClient) ListProfiles(ctx context.Context, params Query) ([]*Profile, error) {
 	list := make([]*Profile, 0)
-	u := params.WithPath("/v1/profiles").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/profiles")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -27,8 +27,8 @@ type ProfileClaim struct {
 
 func (c *profileClient) ListClaims(ctx context.Context, params Query) ([]*ProfileClaim, error) {
 	list := make([]*ProfileClaim, 0)
-	u := params.WithPath("/v1/profiles/claims").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/profiles/claims")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -26,8 +26,8 @@ type ProfileEvent struct {
 
 func (c *profileClient) ListEvents(ctx context.Context, params Query) ([]*ProfileEvent, error) {
 	list := make([]*ProfileEvent, 0)
-	u := params.WithPath("/v1/profiles/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/profiles/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -82,19 +82,18 @@ type Account struct {
 	Metadata           map[string]Metadata `json:"metadata,omitempty"         tzpro:"-"`
 }
 
-type AccountList []*Account
-
-func (l AccountList) Len() int {
-	return len(l)
+func (a *Account) GetRowId() uint64 {
+	return a.RowId
 }
 
-func (l AccountList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].RowId
+// Balance returns the account's total own balance including staked,
+// unstaked and rollup bond funds.
+func (a Account) Balance() float64 {
+	return a.SpendableBalance + a.StakedBalance + a.UnstakedBalance + a.FrozenRollupBond
 }
 
+type AccountList = client.List[*Account]
+
 type AccountQuery = client.TableQuery[*Account]
 
 func (c *accountClient) NewQuery() *AccountQuery {
@@ -102,45 +101,60 @@ func (c *accountClient) NewQuery() *AccountQuery {
 }
 
 func (c *accountClient) Get(ctx context.Context, addr Address, params Query) (*Account, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	a := &Account{}
-	u := params.WithPath(fmt.Sprintf("/explorer/account/%s", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, a); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/account/%s", addr))
+	if err := c.client.GetQuery(ctx, q, nil, a); err != nil {
 		return nil, err
 	}
 	return a, nil
 }
 
 func (c *accountClient) ListContracts(ctx context.Context, addr Address, params Query) (ContractList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	cc := make(ContractList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/account/%s/contracts", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &cc); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/account/%s/contracts", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &cc); err != nil {
 		return nil, err
 	}
 	return cc, nil
 }
 
 func (c *accountClient) ListOps(ctx context.Context, addr Address, params Query) (OpList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	ops := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/account/%s/operations", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &ops); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/account/%s/operations", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &ops); err != nil {
 		return nil, err
 	}
 	return ops, nil
 }
 
 func (c *accountClient) ListTicketBalances(ctx context.Context, addr Address, params Query) (TicketBalanceList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	list := make(TicketBalanceList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/account/%s/ticket_balances", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/account/%s/ticket_balances", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
 func (c *accountClient) ListTicketEvents(ctx context.Context, addr Address, params Query) (TicketEventList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	list := make(TicketEventList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/account/%s/ticket_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/account/%s/ticket_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
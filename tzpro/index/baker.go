@@ -146,8 +146,8 @@ type Snapshot struct {
 
 func (c *bakerClient) Get(ctx context.Context, addr Address, params Query) (*Baker, error) {
 	b := &Baker{}
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, b); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s", addr))
+	if err := c.client.GetQuery(ctx, q, nil, b); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -155,8 +155,8 @@ func (c *bakerClient) Get(ctx context.Context, addr Address, params Query) (*Bak
 
 func (c *bakerClient) List(ctx context.Context, params Query) (BakerList, error) {
 	b := make([]*Baker, 0)
-	u := params.WithPath("/explorer/bakers").Url()
-	if err := c.client.Get(ctx, u, nil, &b); err != nil {
+	q := params.WithPath("/explorer/bakers")
+	if err := c.client.GetQuery(ctx, q, nil, &b); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -164,8 +164,8 @@ func (c *bakerClient) List(ctx context.Context, params Query) (BakerList, error)
 
 func (c *bakerClient) ListVotes(ctx context.Context, addr Address, params Query) (BallotList, error) {
 	cc := make([]*Ballot, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/votes", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &cc); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/votes", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &cc); err != nil {
 		return nil, err
 	}
 	return cc, nil
@@ -173,8 +173,8 @@ func (c *bakerClient) ListVotes(ctx context.Context, addr Address, params Query)
 
 func (c *bakerClient) ListEndorsements(ctx context.Context, addr Address, params Query) (OpList, error) {
 	ops := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/endorsements", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &ops); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/endorsements", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &ops); err != nil {
 		return nil, err
 	}
 	return ops, nil
@@ -182,8 +182,8 @@ func (c *bakerClient) ListEndorsements(ctx context.Context, addr Address, params
 
 func (c *bakerClient) ListDelegations(ctx context.Context, addr Address, params Query) (OpList, error) {
 	ops := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/delegations", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &ops); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/delegations", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &ops); err != nil {
 		return nil, err
 	}
 	return ops, nil
@@ -191,8 +191,8 @@ func (c *bakerClient) ListDelegations(ctx context.Context, addr Address, params
 
 func (c *bakerClient) GetRights(ctx context.Context, addr Address, cycle int64, params Query) (*Rights, error) {
 	var r Rights
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/rights/%d", addr, cycle)).Url()
-	if err := c.client.Get(ctx, u, nil, &r); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/rights/%d", addr, cycle))
+	if err := c.client.GetQuery(ctx, q, nil, &r); err != nil {
 		return nil, err
 	}
 	return &r, nil
@@ -200,8 +200,8 @@ func (c *bakerClient) GetRights(ctx context.Context, addr Address, cycle int64,
 
 func (c *bakerClient) GetIncome(ctx context.Context, addr Address, cycle int64, params Query) (*Income, error) {
 	var r Income
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/income/%d", addr, cycle)).Url()
-	if err := c.client.Get(ctx, u, nil, &r); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/income/%d", addr, cycle))
+	if err := c.client.GetQuery(ctx, q, nil, &r); err != nil {
 		return nil, err
 	}
 	return &r, nil
@@ -209,8 +209,8 @@ func (c *bakerClient) GetIncome(ctx context.Context, addr Address, cycle int64,
 
 func (c *bakerClient) GetSnapshot(ctx context.Context, addr Address, cycle int64, params Query) (*Snapshot, error) {
 	var r Snapshot
-	u := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/snapshot/%d", addr, cycle)).Url()
-	if err := c.client.Get(ctx, u, nil, &r); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bakers/%s/snapshot/%d", addr, cycle))
+	if err := c.client.GetQuery(ctx, q, nil, &r); err != nil {
 		return nil, err
 	}
 	return &r, nil
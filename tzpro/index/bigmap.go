@@ -32,6 +32,10 @@ type Bigmap struct {
 	ValueTypePrim  Prim      `json:"value_type_prim"  tzpro:"value_type,hex"`
 }
 
+func (b *Bigmap) GetRowId() uint64 {
+	return b.RowId
+}
+
 func (r Bigmap) GetKeyTypedef() Typedef {
 	if !r.KeyType.IsValid() {
 		r.KeyType = r.GetKeyType().Typedef("")
@@ -62,9 +66,67 @@ func (c *contractClient) NewBigmapQuery() *BigmapQuery {
 
 func (c *contractClient) GetBigmap(ctx context.Context, id int64, params Query) (*Bigmap, error) {
 	b := &Bigmap{}
-	u := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d", id)).Url()
-	if err := c.client.Get(ctx, u, nil, b); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d", id))
+	if err := c.client.GetQuery(ctx, q, nil, b); err != nil {
 		return nil, err
 	}
 	return b, nil
 }
+
+// GetBigmapId returns the id of the bigmap referenced at path in storage.
+func (v ContractValue) GetBigmapId(path string) (int64, bool) {
+	return v.GetInt64(path)
+}
+
+// BigmapHandle is a bigmap referenced from contract storage with key and
+// value types resolved for querying its contents.
+type BigmapHandle struct {
+	Id        int64
+	Info      *Bigmap
+	KeyType   Type
+	ValueType Type
+	api       ContractAPI
+}
+
+// ResolveBigmap looks up the bigmap referenced at path in storage. Types
+// are taken from the owner's cached script and fall back to the types
+// reported for the bigmap, e.g. for bigmaps no longer in storage.
+func (v ContractValue) ResolveBigmap(ctx context.Context, api ContractAPI, path string) (*BigmapHandle, error) {
+	id, ok := v.GetBigmapId(path)
+	if !ok {
+		return nil, fmt.Errorf("%s: no bigmap id", path)
+	}
+	bm, err := api.GetBigmap(ctx, id, NewQuery().WithPrim())
+	if err != nil {
+		return nil, err
+	}
+	h := &BigmapHandle{
+		Id:        id,
+		Info:      bm,
+		KeyType:   bm.GetKeyType(),
+		ValueType: bm.GetValueType(),
+		api:       api,
+	}
+	if bm.Contract.IsValid() {
+		script, err := api.LoadScript(ctx, bm.Contract)
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := script.BigmapTypesById[id]; ok {
+			h.KeyType, h.ValueType = t.Left(), t.Right()
+		}
+	}
+	return h, nil
+}
+
+func (h *BigmapHandle) GetValue(ctx context.Context, key string, params Query) (*BigmapValue, error) {
+	return h.api.GetBigmapValue(ctx, h.Id, key, params)
+}
+
+func (h *BigmapHandle) ListValues(ctx context.Context, params Query) (BigmapValueList, error) {
+	return h.api.ListBigmapValues(ctx, h.Id, params)
+}
+
+func (h *BigmapHandle) ListUpdates(ctx context.Context, params Query) (BigmapUpdateList, error) {
+	return h.api.ListBigmapUpdates(ctx, h.Id, params)
+}
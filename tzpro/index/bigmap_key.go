@@ -117,7 +117,7 @@ func (k MultiKey) GetValue(path string) (interface{}, bool) {
 	return util.GetPathValue(util.NonNil(k.named, k.anon, k.single), path)
 }
 
-func (k MultiKey) Walk(path string, fn util.ValueWalkerFunc) error {
+func (k MultiKey) Walk(path string, fn util.ValueWalkerFunc, opts ...WalkOptions) error {
 	val := util.NonNil(k.named, k.anon, k.single)
 	if len(path) > 0 {
 		var ok bool
@@ -126,7 +126,7 @@ func (k MultiKey) Walk(path string, fn util.ValueWalkerFunc) error {
 			return nil
 		}
 	}
-	return util.WalkValueMap(path, val, fn)
+	return util.WalkValueMap(path, val, fn, opts...)
 }
 
 func (k MultiKey) Unmarshal(val interface{}) error {
@@ -74,6 +74,10 @@ type BigmapUpdateRow struct {
 	Value    Prim       `json:"value,omitempty"   tzpro:",hex"`
 }
 
+func (b *BigmapUpdateRow) GetRowId() uint64 {
+	return b.RowId
+}
+
 func (r BigmapUpdateRow) Event() (ev BigmapEvent) {
 	ev.Action = r.Action
 	ev.Id = r.BigmapId
@@ -140,8 +144,8 @@ func (c *contractClient) NewBigmapUpdateQuery() *BigmapUpdateQuery {
 
 func (c *contractClient) ListBigmapUpdates(ctx context.Context, id int64, params Query) (BigmapUpdateList, error) {
 	upd := make(BigmapUpdateList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/updates", id)).Url()
-	if err := c.client.Get(ctx, u, nil, &upd); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/updates", id))
+	if err := c.client.GetQuery(ctx, q, nil, &upd); err != nil {
 		return nil, err
 	}
 	return upd, nil
@@ -149,8 +153,8 @@ func (c *contractClient) ListBigmapUpdates(ctx context.Context, id int64, params
 
 func (c *contractClient) ListBigmapKeyUpdates(ctx context.Context, id int64, key string, params Query) (BigmapUpdateList, error) {
 	upd := make(BigmapUpdateList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/%s/updates", id, key)).Url()
-	if err := c.client.Get(ctx, u, nil, &upd); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/%s/updates", id, key))
+	if err := c.client.GetQuery(ctx, q, nil, &upd); err != nil {
 		return nil, err
 	}
 	return upd, nil
@@ -28,6 +28,10 @@ type BigmapValue struct {
 	ValuePrim *Prim       `json:"value_prim,omitempty"  tzpro:"value,hex"`
 }
 
+func (b *BigmapValue) GetRowId() uint64 {
+	return b.RowId
+}
+
 func (r BigmapValue) AsKey(typ Type) BigmapKey {
 	k, _ := NewKey(typ, *r.KeyPrim)
 	return k
@@ -79,7 +83,7 @@ func (v BigmapValue) GetValue(path string) (interface{}, bool) {
 	return util.GetPathValue(v.Value, path)
 }
 
-func (v BigmapValue) Walk(path string, fn util.ValueWalkerFunc) error {
+func (v BigmapValue) Walk(path string, fn util.ValueWalkerFunc, opts ...WalkOptions) error {
 	val := v.Value
 	if len(path) > 0 {
 		var ok bool
@@ -88,7 +92,7 @@ func (v BigmapValue) Walk(path string, fn util.ValueWalkerFunc) error {
 			return nil
 		}
 	}
-	return util.WalkValueMap(path, val, fn)
+	return util.WalkValueMap(path, val, fn, opts...)
 }
 
 func (v BigmapValue) Unmarshal(val any) error {
@@ -96,18 +100,7 @@ func (v BigmapValue) Unmarshal(val any) error {
 	return json.Unmarshal(buf, val)
 }
 
-type BigmapValueList []*BigmapValue
-
-func (l BigmapValueList) Len() int {
-	return len(l)
-}
-
-func (l BigmapValueList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].RowId
-}
+type BigmapValueList = client.List[*BigmapValue]
 
 type BigmapValueQuery = client.TableQuery[*BigmapValue]
 
@@ -117,8 +110,8 @@ func (c contractClient) NewBigmapValueQuery() *BigmapValueQuery {
 
 func (c *contractClient) GetBigmapValue(ctx context.Context, id int64, key string, params Query) (*BigmapValue, error) {
 	v := &BigmapValue{}
-	u := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/%s", id, key)).Url()
-	if err := c.client.Get(ctx, u, nil, v); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/%s", id, key))
+	if err := c.client.GetQuery(ctx, q, nil, v); err != nil {
 		return nil, err
 	}
 	return v, nil
@@ -126,9 +119,81 @@ func (c *contractClient) GetBigmapValue(ctx context.Context, id int64, key strin
 
 func (c *contractClient) ListBigmapValues(ctx context.Context, id int64, params Query) (BigmapValueList, error) {
 	vals := make(BigmapValueList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/values", id)).Url()
-	if err := c.client.Get(ctx, u, nil, &vals); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/bigmap/%d/values", id))
+	if err := c.client.GetQuery(ctx, q, nil, &vals); err != nil {
+		return nil, err
+	}
+	return vals, nil
+}
+
+// GetBigmapValueAt returns the value stored under key in bigmap id as of
+// block height. Values are decoded using the cached script of the contract
+// owning the bigmap.
+func (c *contractClient) GetBigmapValueAt(ctx context.Context, id int64, key string, height int64) (*ContractValue, error) {
+	params := NewQuery().WithPrim().WithMeta().AndArg("block", height)
+	v, err := c.GetBigmapValue(ctx, id, key, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.decodeBigmapValues(ctx, id, BigmapValueList{v}); err != nil {
+		return nil, err
+	}
+	return &ContractValue{Value: v.Value, Prim: v.ValuePrim}, nil
+}
+
+// ListBigmapValuesAt lists bigmap values as of block height. Use limit and
+// cursor or offset arguments in params to page through large bigmaps.
+func (c *contractClient) ListBigmapValuesAt(ctx context.Context, id int64, height int64, params Query) (BigmapValueList, error) {
+	params = params.Clone().WithPrim().WithMeta().AndArg("block", height)
+	vals, err := c.ListBigmapValues(ctx, id, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.decodeBigmapValues(ctx, id, vals); err != nil {
 		return nil, err
 	}
 	return vals, nil
 }
+
+// decodeBigmapValues fills missing values from value prims. Types are taken
+// from the cached owner script and fall back to bigmap metadata for bigmaps
+// not declared in storage such as temporary bigmaps.
+func (c *contractClient) decodeBigmapValues(ctx context.Context, id int64, vals BigmapValueList) error {
+	var typ Type
+	for _, v := range vals {
+		if v.Value != nil || v.ValuePrim == nil {
+			continue
+		}
+		if !typ.IsValid() {
+			var err error
+			typ, err = c.bigmapValueType(ctx, id, v.Meta)
+			if err != nil {
+				return err
+			}
+		}
+		val := NewValue(typ, *v.ValuePrim)
+		m, err := val.Map()
+		if err != nil {
+			return fmt.Errorf("bigmap %d: %w", id, err)
+		}
+		v.Value = m
+	}
+	return nil
+}
+
+func (c *contractClient) bigmapValueType(ctx context.Context, id int64, meta *BigmapMeta) (Type, error) {
+	if meta != nil && meta.Contract.IsValid() {
+		script, err := c.LoadScript(ctx, meta.Contract)
+		if err != nil {
+			return Type{}, err
+		}
+		if t, ok := script.BigmapTypesById[id]; ok {
+			return t.Right(), nil
+		}
+	}
+	bm, err := c.GetBigmap(ctx, id, NewQuery().WithPrim())
+	if err != nil {
+		return Type{}, err
+	}
+	return bm.GetValueType(), nil
+}
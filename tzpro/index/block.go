@@ -6,12 +6,14 @@ package index
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"blockwatch.cc/tzpro-go/internal/client"
 )
 
 type BlockAPI interface {
+	Get(context.Context, BlockId, Query) (*Block, error)
 	GetHash(context.Context, BlockHash, Query) (*Block, error)
 	GetHead(context.Context, Query) (*Block, error)
 	GetHeight(context.Context, int64, Query) (*Block, error)
@@ -84,6 +86,10 @@ type Block struct {
 	Ops              []*Op               `json:"-"`
 }
 
+func (b *Block) GetRowId() uint64 {
+	return b.RowId
+}
+
 type Head struct {
 	Hash        BlockHash `json:"hash"`
 	ParentHash  BlockHash `json:"predecessor"`
@@ -108,6 +114,22 @@ type BlockId struct {
 	Time   time.Time
 }
 
+// BlockHead selects the current head block in BlockAPI.Get.
+var BlockHead = BlockId{Height: -1}
+
+// String returns the block hash when set, "head" for negative heights
+// or the block height.
+func (i BlockId) String() string {
+	switch {
+	case i.Hash.IsValid():
+		return i.Hash.String()
+	case i.Height < 0:
+		return "head"
+	default:
+		return strconv.FormatInt(i.Height, 10)
+	}
+}
+
 func (i BlockId) IsNextBlock(b *Block) bool {
 	if b == nil {
 		return false
@@ -166,18 +188,7 @@ func (b *Block) Head() *Head {
 	}
 }
 
-type BlockList []*Block
-
-func (l BlockList) Len() int {
-	return len(l)
-}
-
-func (l BlockList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].RowId
-}
+type BlockList = client.List[*Block]
 
 type BlockQuery = client.TableQuery[*Block]
 
@@ -185,10 +196,21 @@ func (c blockClient) NewQuery() *BlockQuery {
 	return client.NewTableQuery[*Block](c.client, "block")
 }
 
+// Get fetches a block by hash when set or by height otherwise.
+// Use BlockHead to fetch the current head block.
+func (c *blockClient) Get(ctx context.Context, id BlockId, params Query) (*Block, error) {
+	b := &Block{}
+	q := params.WithPath(fmt.Sprintf("/explorer/block/%s", id))
+	if err := c.client.GetQuery(ctx, q, nil, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
 func (c *blockClient) GetHash(ctx context.Context, hash BlockHash, params Query) (*Block, error) {
 	b := &Block{}
-	u := params.WithPath(fmt.Sprintf("/explorer/block/%s", hash)).Url()
-	if err := c.client.Get(ctx, u, nil, b); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/block/%s", hash))
+	if err := c.client.GetQuery(ctx, q, nil, b); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -196,8 +218,8 @@ func (c *blockClient) GetHash(ctx context.Context, hash BlockHash, params Query)
 
 func (c *blockClient) GetHead(ctx context.Context, params Query) (*Block, error) {
 	b := &Block{}
-	u := params.WithPath("/explorer/block/head").Url()
-	if err := c.client.Get(ctx, u, nil, b); err != nil {
+	q := params.WithPath("/explorer/block/head")
+	if err := c.client.GetQuery(ctx, q, nil, b); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -205,8 +227,8 @@ func (c *blockClient) GetHead(ctx context.Context, params Query) (*Block, error)
 
 func (c *blockClient) GetHeight(ctx context.Context, height int64, params Query) (*Block, error) {
 	b := &Block{}
-	u := params.WithPath(fmt.Sprintf("/explorer/block/%d", height)).Url()
-	if err := c.client.Get(ctx, u, nil, b); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/block/%d", height))
+	if err := c.client.GetQuery(ctx, q, nil, b); err != nil {
 		return nil, err
 	}
 	return b, nil
@@ -214,8 +236,8 @@ func (c *blockClient) GetHeight(ctx context.Context, height int64, params Query)
 
 func (c blockClient) ListOpsHash(ctx context.Context, hash BlockHash, params Query) (OpList, error) {
 	ops := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/block/%s/operations", hash)).Url()
-	if err := c.client.Get(ctx, u, nil, &ops); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/block/%s/operations", hash))
+	if err := c.client.GetQuery(ctx, q, nil, &ops); err != nil {
 		return nil, err
 	}
 	return ops, nil
@@ -223,8 +245,8 @@ func (c blockClient) ListOpsHash(ctx context.Context, hash BlockHash, params Que
 
 func (c blockClient) ListOpsHeight(ctx context.Context, height int64, params Query) (OpList, error) {
 	ops := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/block/%d/operations", height)).Url()
-	if err := c.client.Get(ctx, u, nil, &ops); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/block/%d/operations", height))
+	if err := c.client.GetQuery(ctx, q, nil, &ops); err != nil {
 		return nil, err
 	}
 	return ops, nil
@@ -58,6 +58,10 @@ type Chain struct {
 	InactiveStakers      int64     `json:"inactive_stakers"`
 }
 
+func (c *Chain) GetRowId() uint64 {
+	return c.RowId
+}
+
 type ChainQuery = client.TableQuery[*Chain]
 
 func (c *explorerClient) NewChainQuery() *ChainQuery {
@@ -24,6 +24,10 @@ type Constant struct {
 	Features    util.StringList `json:"features"`
 }
 
+func (c *Constant) GetRowId() uint64 {
+	return c.RowId
+}
+
 type ConstantQuery = client.TableQuery[*Constant]
 
 func (a contractClient) NewConstantQuery() *ConstantQuery {
@@ -32,8 +36,8 @@ func (a contractClient) NewConstantQuery() *ConstantQuery {
 
 func (c *contractClient) GetConstant(ctx context.Context, addr ExprHash, params Query) (*Constant, error) {
 	cc := &Constant{}
-	u := params.WithPath(fmt.Sprintf("/explorer/constant/%s", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, cc); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/constant/%s", addr))
+	if err := c.client.GetQuery(ctx, q, nil, cc); err != nil {
 		return nil, err
 	}
 	return cc, nil
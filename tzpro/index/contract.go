@@ -5,7 +5,12 @@ package index
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"blockwatch.cc/tzpro-go/internal/client"
@@ -15,12 +20,26 @@ import (
 type ContractAPI interface {
 	Get(context.Context, Address, Query) (*Contract, error)
 	GetScript(context.Context, Address, Query) (*ContractScript, error)
+	LoadScript(context.Context, Address) (*ContractScript, error)
+	AddScript(Address, *ContractScript)
+	PrefetchScripts(context.Context, []Address) error
 	GetStorage(context.Context, Address, Query) (*ContractValue, error)
+	GetStorageTyped(context.Context, Address, any) error
 	ListCalls(context.Context, Address, Query) (OpList, error)
+	ListByCreator(context.Context, Address, Query) (ContractList, error)
+	SubscribeContractCalls(context.Context, Address, Query) (<-chan *Op, <-chan error)
+	ExportCalls(context.Context, Address, int64, int64, Query, io.Writer) error
+	GetStorageSeries(context.Context, Address, SeriesParams) ([]StoragePoint, error)
+	GetDelegationHistory(context.Context, Address) ([]DelegationEvent, error)
+	GetViews(context.Context, Address) (Views, error)
+	RunView(context.Context, Address, string, map[string]any) (ContractValue, error)
+	RunOffchainView(context.Context, Address, OffchainView, map[string]any) (ContractValue, error)
 	GetConstant(context.Context, ExprHash, Query) (*Constant, error)
 	GetBigmap(context.Context, int64, Query) (*Bigmap, error)
 	GetBigmapValue(context.Context, int64, string, Query) (*BigmapValue, error)
 	ListBigmapValues(context.Context, int64, Query) (BigmapValueList, error)
+	GetBigmapValueAt(context.Context, int64, string, int64) (*ContractValue, error)
+	ListBigmapValuesAt(context.Context, int64, int64, Query) (BigmapValueList, error)
 	ListBigmapKeyUpdates(context.Context, int64, string, Query) (BigmapUpdateList, error)
 	ListBigmapUpdates(context.Context, int64, Query) (BigmapUpdateList, error)
 	ListTickets(context.Context, Address, Query) (TicketList, error)
@@ -43,6 +62,9 @@ type contractClient struct {
 	client *client.Client
 }
 
+// Contract is returned by explorer and table endpoints. Fields tagged
+// tzpro:"-" are not table columns and remain empty in table results.
+// Hex encoded Script and Storage columns are decoded from binary.
 type Contract struct {
 	RowId         uint64               `json:"row_id,omitempty"`
 	AccountId     uint64               `json:"account_id,omitempty"`
@@ -73,6 +95,10 @@ type Contract struct {
 	Metadata      map[string]*Metadata `json:"metadata,omitempty"  tzpro:"-"`
 }
 
+func (c *Contract) GetRowId() uint64 {
+	return c.RowId
+}
+
 func (c *Contract) Meta() *Metadata {
 	m, ok := c.Metadata[c.Address.String()]
 	if !ok {
@@ -85,19 +111,280 @@ func (c *Contract) Meta() *Metadata {
 	return m
 }
 
-type ContractList []*Contract
+// HasFeature reports whether the contract uses feature name, ignoring case.
+func (c *Contract) HasFeature(name string) bool {
+	return c.Features.ContainsFold(name)
+}
 
-func (l ContractList) Len() int {
-	return len(l)
+// HasInterface reports whether the contract implements interface name,
+// ignoring case.
+func (c *Contract) HasInterface(name string) bool {
+	return c.Interfaces.ContainsFold(name)
 }
 
-func (l ContractList) Cursor() uint64 {
-	if len(l) == 0 {
+// Age returns the time since the contract was first seen on chain. It is
+// zero for nil contracts or when FirstSeenTime was not loaded.
+func (c *Contract) Age() time.Duration {
+	if c == nil || c.FirstSeenTime.IsZero() {
 		return 0
 	}
-	return l[len(l)-1].RowId
+	return time.Since(c.FirstSeenTime)
+}
+
+// Active reports whether the contract was last seen within window. It is
+// false for nil contracts or when LastSeenTime was not loaded.
+func (c *Contract) Active(window time.Duration) bool {
+	if c == nil || c.LastSeenTime.IsZero() {
+		return false
+	}
+	return time.Since(c.LastSeenTime) <= window
+}
+
+// EntrypointStat is the number of calls to a single entrypoint.
+type EntrypointStat struct {
+	Entrypoint string
+	Calls      int
 }
 
+// TopEntrypoints returns up to n entrypoints sorted by call count in
+// descending order. Ties are broken by entrypoint name. When n <= 0
+// all entrypoints are returned.
+func (c *Contract) TopEntrypoints(n int) []EntrypointStat {
+	list := make([]EntrypointStat, 0, len(c.CallStats))
+	for k, v := range c.CallStats {
+		list = append(list, EntrypointStat{Entrypoint: k, Calls: v})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Calls != list[j].Calls {
+			return list[i].Calls > list[j].Calls
+		}
+		return list[i].Entrypoint < list[j].Entrypoint
+	})
+	if n > 0 && n < len(list) {
+		list = list[:n]
+	}
+	return list
+}
+
+// TotalCalls returns the sum of calls across all entrypoints.
+func (c *Contract) TotalCalls() int {
+	var n int
+	for _, v := range c.CallStats {
+		n += v
+	}
+	return n
+}
+
+// ContractKind is a coarse classification of contracts used for display
+// and routing purposes.
+type ContractKind string
+
+const (
+	ContractKindUnknown  ContractKind = ""
+	ContractKindToken    ContractKind = "token"
+	ContractKindNFT      ContractKind = "nft"
+	ContractKindDex      ContractKind = "dex"
+	ContractKindMultisig ContractKind = "multisig"
+)
+
+// ContractKindRule matches contracts which implement all listed interfaces,
+// use all listed features and have all listed entrypoints and bigmaps.
+// Names are compared ignoring case. Standard names the token standard
+// a matching contract implements, if any.
+type ContractKindRule struct {
+	Kind        ContractKind
+	Standard    string   // e.g. FA1.2
+	Interfaces  []string // TZIP interface identifiers
+	Features    []string
+	Entrypoints []string
+	Bigmaps     []string
+}
+
+// ContractKindRules lists rules evaluated by Contract.Kind in order, the
+// first matching rule wins. Contract.TokenStandards reports standards of
+// all matching rules. Rules are heuristics, applications may add private
+// rules in front.
+var ContractKindRules = []ContractKindRule{
+	{Kind: ContractKindNFT, Standard: "FA2", Interfaces: []string{"TZIP-012"}, Entrypoints: []string{"mint"}, Bigmaps: []string{"ledger", "token_metadata"}},
+	{Kind: ContractKindToken, Standard: "FA2", Interfaces: []string{"TZIP-012"}},
+	{Kind: ContractKindToken, Standard: "FA1.2", Interfaces: []string{"TZIP-007"}},
+	{Kind: ContractKindToken, Standard: "FA1", Interfaces: []string{"TZIP-005"}},
+	{Kind: ContractKindDex, Entrypoints: []string{"tezToTokenPayment", "tokenToTezPayment"}},
+	{Kind: ContractKindDex, Entrypoints: []string{"xtzToToken", "tokenToXtz"}},
+	{Kind: ContractKindMultisig, Entrypoints: []string{"create_proposal", "sign_proposal"}},
+	{Kind: ContractKindMultisig, Entrypoints: []string{"main", "default"}, Features: []string{"lambda"}},
+}
+
+func (r ContractKindRule) matches(c *Contract) bool {
+	if len(r.Interfaces)+len(r.Features)+len(r.Entrypoints)+len(r.Bigmaps) == 0 {
+		return false
+	}
+	for _, v := range r.Interfaces {
+		if !c.HasInterface(v) {
+			return false
+		}
+	}
+	for _, v := range r.Features {
+		if !c.HasFeature(v) {
+			return false
+		}
+	}
+	if len(r.Entrypoints) > 0 {
+		eps := c.entrypointNames()
+		for _, v := range r.Entrypoints {
+			if !containsFold(eps, v) {
+				return false
+			}
+		}
+	}
+	for _, v := range r.Bigmaps {
+		if !c.hasBigmap(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// entrypointNames returns entrypoint names from the script when loaded
+// and from call statistics otherwise.
+func (c *Contract) entrypointNames() []string {
+	if c.Script != nil {
+		if eps, err := c.Script.Entrypoints(false); err == nil {
+			names := make([]string, 0, len(eps))
+			for n := range eps {
+				names = append(names, n)
+			}
+			return names
+		}
+	}
+	names := make([]string, 0, len(c.CallStats))
+	for n := range c.CallStats {
+		names = append(names, n)
+	}
+	return names
+}
+
+func (c *Contract) hasBigmap(name string) bool {
+	for n := range c.Bigmaps {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Kind classifies the contract using ContractKindRules.
+func (c *Contract) Kind() ContractKind {
+	for _, r := range ContractKindRules {
+		if r.matches(c) {
+			return r.Kind
+		}
+	}
+	return ContractKindUnknown
+}
+
+// TokenStandards returns names of all token standards the contract
+// implements according to ContractKindRules.
+func (c *Contract) TokenStandards() []string {
+	list := make([]string, 0)
+	for _, r := range ContractKindRules {
+		if r.Standard != "" && !containsFold(list, r.Standard) && r.matches(c) {
+			list = append(list, r.Standard)
+		}
+	}
+	return list
+}
+
+func (c *Contract) implements(standard string) bool {
+	for _, r := range ContractKindRules {
+		if strings.EqualFold(r.Standard, standard) && r.matches(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Contract) IsFA12() bool {
+	return c.implements("FA1.2")
+}
+
+func (c *Contract) IsFA2() bool {
+	return c.implements("FA2")
+}
+
+// ContractVolatileFields lists JSON names of contract fields that change
+// with regular activity and are ignored by Contract.Equal and Contract.Diff.
+// Applications may change the list to tune change detection.
+var ContractVolatileFields = util.StringList{
+	"row_id",
+	"last_seen",
+	"last_seen_time",
+	"storage",
+	"storage_size",
+	"storage_paid",
+	"storage_hash",
+	"total_fees_used",
+	"call_stats",
+	"n_calls_in",
+	"n_calls_out",
+	"n_calls_failed",
+}
+
+// FieldChange describes a changed contract field by JSON name.
+type FieldChange struct {
+	Field string
+	Old   any
+	New   any
+}
+
+// Equal reports whether c and other are equal ignoring ContractVolatileFields.
+func (c *Contract) Equal(other *Contract) bool {
+	return len(c.Diff(other)) == 0
+}
+
+// Diff lists all fields that differ between c and other ignoring
+// ContractVolatileFields. A nil contract compares like an empty contract.
+func (c *Contract) Diff(other *Contract) []FieldChange {
+	if c == other {
+		return nil
+	}
+	var empty Contract
+	if c == nil {
+		c = &empty
+	}
+	if other == nil {
+		other = &empty
+	}
+	var (
+		changes []FieldChange
+		a       = reflect.ValueOf(c).Elem()
+		b       = reflect.ValueOf(other).Elem()
+		typ     = a.Type()
+	)
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || ContractVolatileFields.Contains(name) {
+			continue
+		}
+		x, y := a.Field(i).Interface(), b.Field(i).Interface()
+		if !reflect.DeepEqual(x, y) {
+			changes = append(changes, FieldChange{Field: name, Old: x, New: y})
+		}
+	}
+	return changes
+}
+
+type ContractList = client.List[*Contract]
+
 type ContractQuery = client.TableQuery[*Contract]
 
 func (a contractClient) NewQuery() *ContractQuery {
@@ -105,63 +392,152 @@ func (a contractClient) NewQuery() *ContractQuery {
 }
 
 func (c *contractClient) Get(ctx context.Context, addr Address, params Query) (*Contract, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	cc := &Contract{}
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, cc); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s", addr))
+	if err := c.client.GetQuery(ctx, q, nil, cc); err != nil {
 		return nil, err
 	}
 	return cc, nil
 }
 
 func (c *contractClient) GetScript(ctx context.Context, addr Address, params Query) (*ContractScript, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	cc := &ContractScript{}
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s/script", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, cc); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s/script", addr))
+	if err := c.client.GetQuery(ctx, q, nil, cc); err != nil {
 		return nil, err
 	}
 	return cc, nil
 }
 
 func (c *contractClient) GetStorage(ctx context.Context, addr Address, params Query) (*ContractValue, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
+	// post-processing unpack requires prim
+	unpack := params.Query.Has("unpack")
+	if unpack {
+		params = params.Clone().WithPrim()
+	}
 	cc := &ContractValue{}
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s/storage", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, cc); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s/storage", addr))
+	if err := c.client.GetQuery(ctx, q, nil, cc); err != nil {
 		return nil, err
 	}
+	if unpack && cc.HasPacked() {
+		script, err := c.LoadScript(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+		up, err := cc.UnpackAll(script.Script.StorageType())
+		if err != nil {
+			return nil, err
+		}
+		cc = &up
+	}
 	return cc, nil
 }
 
+// GetStorageTyped loads storage of contract addr and decodes it
+// into out which must be a pointer to a struct whose json tags match field
+// annotations. Storage is rendered from prim data using the contract's
+// type from the script cache, so only the first call fetches the script.
+func (c *contractClient) GetStorageTyped(ctx context.Context, addr Address, out any) error {
+	script, err := c.LoadScript(ctx, addr)
+	if err != nil {
+		return err
+	}
+	store, err := c.GetStorage(ctx, addr, NewQuery().WithPrim())
+	if err != nil {
+		return err
+	}
+	if store.Prim == nil {
+		return ErrNoStorage
+	}
+	val := NewValue(script.Script.StorageType(), *store.Prim)
+	m, err := val.Map()
+	if err != nil {
+		return err
+	}
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, out)
+}
+
 func (c *contractClient) ListCalls(ctx context.Context, addr Address, params Query) (OpList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	calls := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s/calls", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &calls); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s/calls", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &calls); err != nil {
 		return nil, err
 	}
 	return calls, nil
 }
 
+// ListByCreator lists contracts deployed by creator using the
+// contract table. Limit, cursor and order from params are applied, other
+// arguments are passed to the table endpoint as is.
+func (c *contractClient) ListByCreator(ctx context.Context, creator Address, params Query) (ContractList, error) {
+	if err := client.CheckAddress(creator); err != nil {
+		return nil, err
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	q := c.NewQuery().AndEqual("creator", creator)
+	for k, v := range params.Query {
+		q.Query.Query[k] = append([]string(nil), v...)
+	}
+	if o := params.Query.Get("order"); o != "" {
+		q.WithOrder(client.OrderType(o))
+	}
+	res, err := q.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ContractList(res.Rows()), nil
+}
+
 func (c *contractClient) ListTickets(ctx context.Context, addr Address, params Query) (TicketList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	list := make(TicketList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s/tickets", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s/tickets", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
 func (c *contractClient) ListTicketBalances(ctx context.Context, addr Address, params Query) (TicketBalanceList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	list := make(TicketBalanceList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s/ticket_balances", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s/ticket_balances", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
 func (c *contractClient) ListTicketEvents(ctx context.Context, addr Address, params Query) (TicketEventList, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
 	list := make(TicketEventList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/contract/%s/ticket_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/contract/%s/ticket_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -5,32 +5,168 @@ package index
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"blockwatch.cc/tzgo/micheline"
+	"blockwatch.cc/tzpro-go/internal/client"
 )
 
 func (c *opClient) loadScript(ctx context.Context, addr Address) (*ContractScript, error) {
-	if script, ok := c.client.CacheGet(addr); ok {
-		return script.(*ContractScript), nil
-	}
-	api := NewContractAPI(c.client)
-	script, err := api.GetScript(ctx, addr, NewQuery().WithPrim())
+	script, err := c.client.CacheLoad(addr, func() (any, error) {
+		api := NewContractAPI(c.client)
+		script, err := api.GetScript(ctx, addr, NewQuery().WithPrim())
+		if err != nil {
+			return nil, err
+		}
+		if err := script.prepareCache(c.client.KeepCode()); err != nil {
+			c.client.Logger().Warnf("script %s: %v", addr, err)
+		}
+		return script, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	// strip code
-	script.Script.Code.Code = micheline.Prim{}
-	script.Script.Code.View = micheline.Prim{}
-	// fill bigmap type info
-	script.BigmapNames = script.Script.Bigmaps()
-	script.BigmapTypes = script.Script.BigmapTypes()
-	script.BigmapTypesById = make(map[int64]Type)
-	for n, v := range script.BigmapTypes {
-		id := script.BigmapNames[n]
-		script.BigmapTypesById[id] = v
-	}
-	c.client.CacheAdd(addr, script)
-	return script, nil
+	return script.(*ContractScript), nil
+}
+
+// StripCode removes contract code and views from s keeping only types
+// and storage.
+func (s *ContractScript) StripCode() {
+	if s == nil || s.Script == nil {
+		return
+	}
+	s.Script.Code.Code = micheline.Prim{}
+	s.Script.Code.View = micheline.Prim{}
+}
+
+// prepareCache strips code unless keepCode is set and fills view and
+// bigmap type info for use as cached script. An error is returned when
+// views cannot be decoded; the script is still prepared but lacks views.
+func (s *ContractScript) prepareCache(keepCode bool) error {
+	// keep view signatures before code is stripped
+	var err error
+	if len(s.Views) == 0 && s.Script != nil {
+		if s.Views, err = s.Script.Views(false, false); err != nil {
+			err = fmt.Errorf("decoding views: %w", err)
+		}
+	}
+	if !keepCode {
+		s.StripCode()
+	}
+	if s.Script == nil {
+		return err
+	}
+	// keep bigmap info of scripts without storage
+	if names := s.Script.Bigmaps(); len(names) > 0 || len(s.BigmapNames) == 0 {
+		s.BigmapNames = names
+	}
+	s.BigmapTypes = s.Script.BigmapTypes()
+	s.BigmapTypesById = make(map[int64]Type)
+	for n, v := range s.BigmapTypes {
+		id := s.BigmapNames[n]
+		s.BigmapTypesById[id] = v
+	}
+	return err
+}
+
+// AddScript stores a copy of script for contract addr in the script cache,
+// e.g. to preload scripts from disk. Code is stripped unless the client
+// keeps code.
+func (c *contractClient) AddScript(addr Address, script *ContractScript) {
+	cp := *script
+	if script.Script != nil {
+		sc := *script.Script
+		cp.Script = &sc
+	}
+	if err := cp.prepareCache(c.client.KeepCode()); err != nil {
+		c.client.Logger().Warnf("script %s: %v", addr, err)
+	}
+	c.client.CacheAdd(addr, &cp)
+}
+
+// LoadScript returns the script of contract addr from the client's script
+// cache and fetches it on a miss. Code is stripped from cached scripts.
+func (c *contractClient) LoadScript(ctx context.Context, addr Address) (*ContractScript, error) {
+	return (&opClient{client: c.client}).loadScript(ctx, addr)
+}
+
+// LoadScript returns the contract's script using the script cache of api.
+// Use it when the contract was loaded without script data.
+func (c *Contract) LoadScript(ctx context.Context, api ContractAPI) (*ContractScript, error) {
+	return api.LoadScript(ctx, c.Address)
+}
+
+// PrefetchConcurrency limits the number of scripts loaded in parallel by
+// PrefetchScripts.
+var PrefetchConcurrency = 8
+
+// PrefetchScripts loads scripts for all addrs into the script cache using
+// up to PrefetchConcurrency parallel requests. Cached scripts and duplicate
+// addresses are loaded only once. Addresses which failed with a transient
+// error are skipped and returned as *BatchError after all other scripts
+// were loaded. The first other error cancels the prefetch and is returned.
+func (c *contractClient) PrefetchScripts(ctx context.Context, addrs []Address) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := PrefetchConcurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	var (
+		queue = make(chan Address)
+		wg    sync.WaitGroup
+		once  sync.Once
+		fatal error
+		mu    sync.Mutex
+		batch = client.NewBatchError[Address]()
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for addr := range queue {
+				_, err := c.LoadScript(ctx, addr)
+				switch {
+				case err == nil:
+				case ctx.Err() == nil && client.IsTransient(err):
+					mu.Lock()
+					batch.Add(addr, err)
+					mu.Unlock()
+				default:
+					once.Do(func() {
+						fatal = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	seen := make(map[Address]struct{}, len(addrs))
+feed:
+	for _, addr := range addrs {
+		if _, ok := seen[addr]; ok || !addr.IsContract() {
+			continue
+		}
+		seen[addr] = struct{}{}
+		select {
+		case queue <- addr:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if fatal != nil {
+		return fatal
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return batch.ErrorOrNil()
 }
 
 // func (c *Client) AddCachedScript(addr Address, script *micheline.Script) {
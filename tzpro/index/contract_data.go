@@ -4,10 +4,20 @@
 package index
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"blockwatch.cc/tzgo/micheline"
 	"blockwatch.cc/tzpro-go/internal/util"
 )
 
@@ -21,6 +31,31 @@ type ContractParameters struct {
 	Result        json.RawMessage `json:"result,omitempty"`     // rollup
 }
 
+func (p *ContractParameters) UnmarshalJSON(buf []byte) error {
+	type alias struct {
+		Entrypoint string          `json:"entrypoint,omitempty"`
+		L2Address  *Address        `json:"l2_address,omitempty"`
+		Kind       string          `json:"kind,omitempty"`
+		Method     string          `json:"method,omitempty"`
+		Args       json.RawMessage `json:"args,omitempty"`
+		Result     json.RawMessage `json:"result,omitempty"`
+	}
+	var a alias
+	if err := json.Unmarshal(buf, &a); err != nil {
+		return err
+	}
+	if err := p.ContractValue.UnmarshalJSON(buf); err != nil {
+		return err
+	}
+	p.Entrypoint = a.Entrypoint
+	p.L2Address = a.L2Address
+	p.Kind = a.Kind
+	p.Method = a.Method
+	p.Args = a.Args
+	p.Result = a.Result
+	return nil
+}
+
 type ContractScript struct {
 	Script          *Script          `json:"script,omitempty"`
 	StorageType     Typedef          `json:"storage_type"`
@@ -39,6 +74,189 @@ func (s ContractScript) Types() (param, store Type, eps Entrypoints, bigmaps map
 	return
 }
 
+// Describe renders storage type, entrypoints and views as an indented
+// tree including annotations. Entrypoints are listed by id and views by
+// name so output is deterministic.
+func (s ContractScript) Describe() string {
+	var b strings.Builder
+	store := s.StorageType
+	if !store.IsValid() && s.Script != nil {
+		store = s.Script.StorageType().Typedef("")
+	}
+	b.WriteString("storage:\n")
+	describeTypedef(&b, store, 1)
+
+	eps := s.Entrypoints
+	if len(eps) == 0 && s.Script != nil {
+		eps, _ = s.Script.Entrypoints(true)
+	}
+	list := make([]micheline.Entrypoint, 0, len(eps))
+	for _, ep := range eps {
+		list = append(list, ep)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	b.WriteString("entrypoints:\n")
+	for _, ep := range list {
+		b.WriteString("  ")
+		b.WriteString(ep.Name)
+		b.WriteString("\n")
+		for _, v := range ep.Typedef {
+			describeTypedef(&b, v, 2)
+		}
+	}
+
+	views := s.Views
+	if len(views) == 0 && s.Script != nil {
+		views, _ = s.Script.Views(false, false)
+	}
+	if len(views) > 0 {
+		names := make([]string, 0, len(views))
+		for n := range views {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		b.WriteString("views:\n")
+		for _, n := range names {
+			v := views[n]
+			b.WriteString("  ")
+			b.WriteString(n)
+			b.WriteString("\n")
+			if v.Param.IsValid() {
+				describeTypedef(&b, v.Param.Typedef("@param"), 2)
+			}
+			if v.Retval.IsValid() {
+				describeTypedef(&b, v.Retval.Typedef("@return"), 2)
+			}
+		}
+	}
+	return b.String()
+}
+
+func describeTypedef(b *strings.Builder, t Typedef, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if t.Name != "" {
+		b.WriteString(t.Name)
+		b.WriteString(": ")
+	}
+	if t.Optional {
+		b.WriteString("option ")
+	}
+	b.WriteString(t.Type)
+	b.WriteString("\n")
+	for _, v := range t.Args {
+		describeTypedef(b, v, depth+1)
+	}
+}
+
+// InterfaceSignature fingerprints a standard contract interface by the
+// names of entrypoints it requires.
+type InterfaceSignature struct {
+	Name        string
+	Entrypoints []string
+}
+
+// KnownInterfaceSignatures lists interfaces detected by DetectInterfaces.
+// Applications may append private signatures.
+var KnownInterfaceSignatures = []InterfaceSignature{
+	{Name: "TZIP-005", Entrypoints: []string{"transfer", "getBalance", "getTotalSupply"}},
+	{Name: "TZIP-007", Entrypoints: []string{"transfer", "approve", "getAllowance", "getBalance", "getTotalSupply"}},
+	{Name: "TZIP-012", Entrypoints: []string{"transfer", "balance_of", "update_operators"}},
+}
+
+// DetectInterfaces matches the script's entrypoints against known interface
+// signatures. When entrypoint types are available, interfaces known to tzgo
+// are also checked for matching argument types.
+func (s ContractScript) DetectInterfaces() []string {
+	eps := s.Entrypoints
+	if len(eps) == 0 && s.Script != nil {
+		eps, _ = s.Script.Entrypoints(true)
+	}
+	hasPrims := len(eps) > 0
+	for _, ep := range eps {
+		hasPrims = hasPrims && ep.Prim != nil
+	}
+	list := make([]string, 0)
+	for _, sig := range KnownInterfaceSignatures {
+		matched := true
+		for _, name := range sig.Entrypoints {
+			if _, ok := eps[name]; !ok {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		iface := micheline.Interface(sig.Name)
+		if _, ok := micheline.InterfaceSpecs[iface]; ok && hasPrims && !iface.Matches(eps) {
+			continue
+		}
+		list = append(list, sig.Name)
+	}
+	return list
+}
+
+// BuildParameters marshals named Go values in args into a Micheline
+// parameter tree suitable for calling entrypoint. Values are matched
+// to entrypoint arguments by annotation name. Missing non-optional
+// arguments and type mismatches are reported as error.
+func (s ContractScript) BuildParameters(entrypoint string, args map[string]any) (Prim, error) {
+	eps := s.Entrypoints
+	if len(eps) == 0 && s.Script != nil {
+		eps, _ = s.Script.Entrypoints(true)
+	}
+	ep, ok := eps[entrypoint]
+	if !ok {
+		return Prim{}, fmt.Errorf("unknown entrypoint %q", entrypoint)
+	}
+	switch len(ep.Typedef) {
+	case 0:
+		return micheline.NewCode(micheline.D_UNIT), nil
+	case 1:
+		typ := ep.Typedef[0]
+		if typ.Type == micheline.TypeStruct {
+			if err := checkArgs(typ.Args, args); err != nil {
+				return Prim{}, fmt.Errorf("entrypoint %s: %w", entrypoint, err)
+			}
+		} else if !typ.Optional && typ.OpCode() != micheline.T_UNIT {
+			if _, ok := args[typ.Name]; !ok {
+				return Prim{}, fmt.Errorf("entrypoint %s: missing arg %q", entrypoint, typ.Name)
+			}
+		}
+		prim, err := typ.Marshal(args, true)
+		if err != nil {
+			return Prim{}, fmt.Errorf("entrypoint %s: %w", entrypoint, err)
+		}
+		return prim, nil
+	default:
+		if err := checkArgs(ep.Typedef, args); err != nil {
+			return Prim{}, fmt.Errorf("entrypoint %s: %w", entrypoint, err)
+		}
+		typ := Typedef{
+			Name: micheline.CONST_ENTRYPOINT,
+			Type: micheline.TypeStruct,
+			Args: ep.Typedef,
+		}
+		prim, err := typ.Marshal(args, true)
+		if err != nil {
+			return Prim{}, fmt.Errorf("entrypoint %s: %w", entrypoint, err)
+		}
+		return prim, nil
+	}
+}
+
+func checkArgs(typs []Typedef, args map[string]any) error {
+	for _, v := range typs {
+		if v.Optional || v.OpCode() == micheline.T_UNIT {
+			continue
+		}
+		if _, ok := args[v.Name]; !ok {
+			return fmt.Errorf("missing arg %q", v.Name)
+		}
+	}
+	return nil
+}
+
 type ContractValue struct {
 	Value any   `json:"value,omitempty"`
 	Prim  *Prim `json:"prim,omitempty"`
@@ -56,8 +274,45 @@ func (v ContractValue) IsPrim() bool {
 	}
 }
 
+// UseJSONNumber controls whether numbers in ContractValue.Value decode as
+// json.Number instead of float64. This keeps large nat and int values
+// exact. Getters accept both representations.
+var UseJSONNumber = true
+
+// UnmarshalJSON decodes a contract value. When the server returns a bare
+// Micheline tree as value, Prim is populated directly from the raw JSON so
+// that annotations are preserved.
+func (v *ContractValue) UnmarshalJSON(buf []byte) error {
+	type alias struct {
+		Value json.RawMessage `json:"value,omitempty"`
+		Prim  *Prim           `json:"prim,omitempty"`
+	}
+	var a alias
+	if err := json.Unmarshal(buf, &a); err != nil {
+		return err
+	}
+	v.Value = nil
+	v.Prim = a.Prim
+	if len(a.Value) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(a.Value))
+		if UseJSONNumber {
+			dec.UseNumber()
+		}
+		if err := dec.Decode(&v.Value); err != nil {
+			return err
+		}
+	}
+	if v.Prim == nil && v.IsPrim() {
+		p := Prim{}
+		if err := p.UnmarshalJSON(a.Value); err == nil {
+			v.Prim = &p
+		}
+	}
+	return nil
+}
+
 func (v ContractValue) AsPrim() (Prim, bool) {
-	if v.Prim.IsValid() {
+	if v.Prim != nil && v.Prim.IsValid() {
 		return *v.Prim, true
 	}
 
@@ -71,6 +326,15 @@ func (v ContractValue) AsPrim() (Prim, bool) {
 	return Prim{}, false
 }
 
+// MustPrim returns the Micheline tree and panics when no prim data is present.
+func (v ContractValue) MustPrim() Prim {
+	p, ok := v.AsPrim()
+	if !ok {
+		panic("tzpro: contract value has no prim data")
+	}
+	return p
+}
+
 func (v ContractValue) Has(path string) bool {
 	return util.HasPath(v.Value, path)
 }
@@ -87,10 +351,114 @@ func (v ContractValue) GetBig(path string) (*big.Int, bool) {
 	return util.GetPathBig(v.Value, path)
 }
 
+// GetBigInt is an alias for GetBig.
+func (v ContractValue) GetBigInt(path string) (*big.Int, bool) {
+	return v.GetBig(path)
+}
+
+// GetBigChecked returns the number at path validated against Micheline
+// type t. Values of type nat and mutez must not be negative.
+func (v ContractValue) GetBigChecked(path string, t Type) (*big.Int, error) {
+	b, ok := util.GetPathBig(v.Value, path)
+	if !ok {
+		return nil, fmt.Errorf("%s: missing or not a number", path)
+	}
+	switch t.OpCode {
+	case micheline.T_INT:
+	case micheline.T_NAT, micheline.T_MUTEZ:
+		if b.Sign() < 0 {
+			return nil, fmt.Errorf("%s: negative value %s for type %s", path, b, t.OpCode)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported type %s", path, t.OpCode)
+	}
+	return b, nil
+}
+
 func (v ContractValue) GetZ(path string) (Z, bool) {
 	return util.GetPathZ(v.Value, path)
 }
 
+// GetUnpacked decodes bytes at path that contain a PACKed Micheline value
+// (0x05 prefix) and returns it as new value typed by t. When t is invalid
+// only the Prim tree is populated.
+func (v ContractValue) GetUnpacked(path string, t Type) (ContractValue, bool) {
+	s, ok := util.GetPathString(v.Value, path)
+	if !ok {
+		return ContractValue{}, false
+	}
+	buf, err := hex.DecodeString(s)
+	if err != nil || len(buf) < 2 || buf[0] != 0x05 {
+		return ContractValue{}, false
+	}
+	var prim Prim
+	if err := prim.UnmarshalBinary(buf[1:]); err != nil {
+		return ContractValue{}, false
+	}
+	res := ContractValue{Prim: &prim}
+	if t.IsValid() {
+		val := NewValue(t, prim)
+		m, err := val.Map()
+		if err != nil {
+			return ContractValue{}, false
+		}
+		res.Value = m
+	}
+	return res, true
+}
+
+// HasPacked reports whether any leaf of the decoded value is a hex string
+// holding a PACKed Micheline value (0x05 prefix).
+func (v ContractValue) HasPacked() bool {
+	var found bool
+	_ = util.WalkValueMap("", v.Value, func(_ string, val any) error {
+		if s, ok := val.(string); ok && isPackedHex(s) {
+			found = true
+			return util.ErrStopWalk
+		}
+		return nil
+	})
+	return found
+}
+
+// UnpackAll decodes all PACKed bytes in v recursively and renders the
+// result using type t. It requires the Prim tree, i.e. values loaded with
+// WithPrim. Unpacking walks and re-renders the entire value which is
+// expensive on large storage, prefer server-side unpacking and call this
+// only when HasPacked reports leftovers.
+func (v ContractValue) UnpackAll(t Type) (ContractValue, error) {
+	if v.Prim == nil {
+		return v, ErrNoStorage
+	}
+	if !t.IsValid() {
+		return v, ErrNoType
+	}
+	if !v.Prim.IsPackedAny() {
+		return v, nil
+	}
+	val, err := NewValue(t, *v.Prim).UnpackAll()
+	if err != nil {
+		return v, err
+	}
+	m, err := val.Map()
+	if err != nil {
+		return v, err
+	}
+	return ContractValue{Value: m, Prim: &val.Value}, nil
+}
+
+func isPackedHex(s string) bool {
+	if len(s) < 4 || len(s)%2 != 0 || s[:2] != "05" {
+		return false
+	}
+	buf, err := hex.DecodeString(s)
+	if err != nil {
+		return false
+	}
+	var prim Prim
+	return prim.UnmarshalBinary(buf[1:]) == nil
+}
+
 func (v ContractValue) GetTime(path string) (time.Time, bool) {
 	return util.GetPathTime(v.Value, path)
 }
@@ -99,11 +467,19 @@ func (v ContractValue) GetAddress(path string) (Address, bool) {
 	return util.GetPathAddress(v.Value, path)
 }
 
+func (v ContractValue) GetKeyHash(path string) (Address, bool) {
+	return util.GetPathKeyHash(v.Value, path)
+}
+
+func (v ContractValue) GetPublicKey(path string) (Key, bool) {
+	return util.GetPathKey(v.Value, path)
+}
+
 func (v ContractValue) GetValue(path string) (interface{}, bool) {
 	return util.GetPathValue(v.Value, path)
 }
 
-func (v ContractValue) Walk(path string, fn util.ValueWalkerFunc) error {
+func (v ContractValue) Walk(path string, fn util.ValueWalkerFunc, opts ...WalkOptions) error {
 	val := v.Value
 	if len(path) > 0 {
 		var ok bool
@@ -112,10 +488,145 @@ func (v ContractValue) Walk(path string, fn util.ValueWalkerFunc) error {
 			return nil
 		}
 	}
-	return util.WalkValueMap(path, val, fn)
+	return util.WalkValueMap(path, val, fn, opts...)
+}
+
+// Range calls fn for each entry of the map or list at path. Maps may be
+// encoded as JSON object or as list of key/value objects. Object keys are
+// visited in sorted order, list entries in order using their position as
+// key. Returning ErrStopWalk from fn stops iteration without error.
+func (v ContractValue) Range(path string, fn func(key string, val ContractValue) error) error {
+	val := v.Value
+	if len(path) > 0 {
+		var ok bool
+		val, ok = util.GetPathValue(val, path)
+		if !ok {
+			return fmt.Errorf("%s: path not found", path)
+		}
+	}
+	var err error
+	switch t := val.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err = fn(k, ContractValue{Value: t[k]}); err != nil {
+				break
+			}
+		}
+	case []any:
+		for i, e := range t {
+			key, val := strconv.Itoa(i), e
+			if m, ok := e.(map[string]any); ok && len(m) == 2 {
+				k, hasKey := m["key"]
+				v, hasVal := m["value"]
+				if hasKey && hasVal {
+					key, val = rangeKey(k), v
+				}
+			}
+			if err = fn(key, ContractValue{Value: val}); err != nil {
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("%s: value is not a map or list", path)
+	}
+	if errors.Is(err, ErrStopWalk) {
+		err = nil
+	}
+	return err
+}
+
+func rangeKey(k any) string {
+	switch k.(type) {
+	case map[string]any, []any:
+		buf, _ := json.Marshal(k)
+		return string(buf)
+	default:
+		return util.ToString(k)
+	}
 }
 
 func (v ContractValue) Unmarshal(val interface{}) error {
 	buf, _ := json.Marshal(v.Value)
 	return json.Unmarshal(buf, val)
 }
+
+// JSON returns a canonical encoding of Value for content hashing. Object
+// keys are sorted, insignificant whitespace is omitted and HTML characters
+// are not escaped.
+func (v ContractValue) JSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v.Value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}), nil
+}
+
+// Hash returns the hex encoded SHA256 hash of the canonical JSON encoding
+// of Value. Equal values yield equal hashes.
+func (v ContractValue) Hash() string {
+	buf, err := v.JSON()
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(buf)
+	return hex.EncodeToString(h[:])
+}
+
+// ValueChangeKind classifies a ValueChange.
+type ValueChangeKind string
+
+const (
+	ValueAdded       ValueChangeKind = "added"
+	ValueRemoved     ValueChangeKind = "removed"
+	ValueChanged     ValueChangeKind = "changed"
+	ValueTypeChanged ValueChangeKind = "type_changed"
+)
+
+// ValueChange is a difference at a single leaf of two contract values.
+type ValueChange struct {
+	Path string // dotted path as used by ContractValue getters
+	Kind ValueChangeKind
+	Old  any // nil when added
+	New  any // nil when removed
+}
+
+// DiffContractValues compares leaves of two decoded contract values and
+// returns all added, removed and changed paths sorted by path. A leaf
+// whose Go type differs, e.g. a string that became a number, is reported
+// as ValueTypeChanged.
+func DiffContractValues(prev, next ContractValue) []ValueChange {
+	a, b := flattenValue(prev.Value), flattenValue(next.Value)
+	paths := make([]string, 0, len(a)+len(b))
+	for p := range a {
+		paths = append(paths, p)
+	}
+	for p := range b {
+		if _, ok := a[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	changes := make([]ValueChange, 0)
+	for _, p := range paths {
+		x, okx := a[p]
+		y, oky := b[p]
+		switch {
+		case !okx:
+			changes = append(changes, ValueChange{Path: p, Kind: ValueAdded, New: y})
+		case !oky:
+			changes = append(changes, ValueChange{Path: p, Kind: ValueRemoved, Old: x})
+		case reflect.TypeOf(x) != reflect.TypeOf(y):
+			changes = append(changes, ValueChange{Path: p, Kind: ValueTypeChanged, Old: x, New: y})
+		case !reflect.DeepEqual(x, y):
+			changes = append(changes, ValueChange{Path: p, Kind: ValueChanged, Old: x, New: y})
+		}
+	}
+	return changes
+}
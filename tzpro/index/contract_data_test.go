@@ -0,0 +1,72 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestContractValueBigNumberRoundTrip(t *testing.T) {
+	const max256 = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	in := `{"value":{"total_supply":` + max256 + `,"ledger":{"balance":"` + max256 + `"}}}`
+
+	var v ContractValue
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want, _ := new(big.Int).SetString(max256, 10)
+	for _, path := range []string{"total_supply", "ledger.balance"} {
+		got, ok := v.GetBig(path)
+		if !ok {
+			t.Fatalf("%s: missing value", path)
+		}
+		if got.Cmp(want) != 0 {
+			t.Errorf("%s: got %s, want %s", path, got, want)
+		}
+	}
+
+	buf, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(buf), `"total_supply":`+max256) {
+		t.Errorf("number not preserved on marshal: %s", buf)
+	}
+}
+
+func TestWrappedStopWalk(t *testing.T) {
+	stop := fmt.Errorf("done: %w", ErrStopWalk)
+	v := ContractValue{Value: map[string]any{"a": "1", "b": "2"}}
+	var n int
+	err := v.Range("", func(string, ContractValue) error {
+		n++
+		return stop
+	})
+	if err != nil || n != 1 {
+		t.Errorf("Range: got error %v after %d calls, want nil after 1", err, n)
+	}
+
+	n = 0
+	err = v.Walk("", func(string, any) error {
+		n++
+		return stop
+	})
+	if err != nil || n != 1 {
+		t.Errorf("Walk: got error %v after %d calls, want nil after 1", err, n)
+	}
+
+	in := &Op{Internal: []*Op{{Internal: []*Op{{}}}, {}}}
+	n = 0
+	err = in.WalkInternal(func(*Op) error {
+		n++
+		return stop
+	})
+	if err != nil || n != 1 {
+		t.Errorf("WalkInternal: got error %v after %d calls, want nil after 1", err, n)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"context"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
+)
+
+// DelegationEvent is a single change of a contract's baker. Baker is zero
+// when the contract withdrew its delegation.
+type DelegationEvent struct {
+	Height    int64
+	Time      time.Time
+	OpHash    OpHash
+	Baker     Address
+	PrevBaker Address
+}
+
+// GetDelegationHistory returns all successful delegation changes of
+// contract addr in ascending order. Contract.Baker only holds the current
+// baker, use this for smart-contract wallets that re-delegate.
+func (c *contractClient) GetDelegationHistory(ctx context.Context, addr Address) ([]DelegationEvent, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
+	var (
+		api    = NewAccountAPI(c.client)
+		events = make([]DelegationEvent, 0)
+		cursor uint64
+	)
+	for {
+		q := NewQuery().
+			Asc().
+			WithLimit(ExportPageSize).
+			AndArg("type", OpTypeDelegation)
+		if cursor > 0 {
+			q = q.WithCursor(cursor)
+		}
+		ops, err := api.ListOps(ctx, addr, q)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range ops {
+			for _, v := range op.Content() {
+				if v.Type != OpTypeDelegation || !v.IsSuccess || !v.Sender.Equal(addr) {
+					continue
+				}
+				events = append(events, DelegationEvent{
+					Height:    op.Height,
+					Time:      op.Timestamp,
+					OpHash:    op.Hash,
+					Baker:     v.Baker,
+					PrevBaker: v.PrevBaker,
+				})
+			}
+		}
+		if uint(len(ops)) < ExportPageSize {
+			return events, nil
+		}
+		cursor = ops.Cursor()
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+var (
+	// ExportChunkSize is the number of blocks fetched by a single export worker.
+	ExportChunkSize int64 = 10000
+
+	// ExportConcurrency limits the number of block ranges fetched in parallel.
+	ExportConcurrency = 4
+
+	// ExportPageSize is the number of calls requested per page.
+	ExportPageSize uint = 500
+)
+
+type exportChunk struct {
+	from, to int64
+	ops      OpList
+	err      error
+	done     chan struct{}
+}
+
+// ExportCalls writes all calls to addr between block heights from
+// and to (inclusive) as newline delimited JSON to w. The range is split
+// into chunks of ExportChunkSize blocks which are fetched concurrently.
+// Output is strictly ordered by height and op id regardless of fetch
+// order. At most ExportConcurrency chunks are kept in memory. Export stops
+// at the first error; data written up to this point remains valid.
+func (c *contractClient) ExportCalls(ctx context.Context, addr Address, from, to int64, params Query, w io.Writer) error {
+	if from < 0 || to < from {
+		return fmt.Errorf("invalid height range %d..%d", from, to)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	size, workers := ExportChunkSize, ExportConcurrency
+	if size <= 0 {
+		size = 1
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	chunks := make([]*exportChunk, 0, (to-from)/size+1)
+	for h := from; h <= to; h += size {
+		chunks = append(chunks, &exportChunk{
+			from: h,
+			to:   min64(h+size-1, to),
+			done: make(chan struct{}),
+		})
+	}
+
+	// slots are acquired before a fetch starts and released after the
+	// chunk has been written which bounds memory use
+	slots := make(chan struct{}, workers)
+	go func() {
+		for _, ch := range chunks {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(ch *exportChunk) {
+				defer close(ch.done)
+				ch.ops, ch.err = c.fetchCallRange(ctx, addr, ch.from, ch.to, params)
+			}(ch)
+		}
+	}()
+
+	enc := json.NewEncoder(w)
+	for _, ch := range chunks {
+		select {
+		case <-ch.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if ch.err != nil {
+			return ch.err
+		}
+		for _, op := range ch.ops {
+			if err := enc.Encode(op); err != nil {
+				return err
+			}
+		}
+		ch.ops = nil
+		<-slots
+	}
+	return nil
+}
+
+// DecodeContractStream reads newline delimited JSON contracts from r and
+// calls fn for each contract. It stops at the first decode error or error
+// returned by fn and returns nil at end of input.
+func DecodeContractStream(r io.Reader, fn func(*Contract) error) error {
+	return decodeStream(r, fn)
+}
+
+// DecodeCallStream reads calls written by ExportCalls from r and
+// calls fn for each operation.
+func DecodeCallStream(r io.Reader, fn func(*Op) error) error {
+	return decodeStream(r, fn)
+}
+
+func decodeStream[T any](r io.Reader, fn func(*T) error) error {
+	dec := json.NewDecoder(r)
+	for n := 1; ; n++ {
+		v := new(T)
+		if err := dec.Decode(v); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("record %d: %w", n, err)
+		}
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchCallRange loads all calls in block range [from, to].
+func (c *contractClient) fetchCallRange(ctx context.Context, addr Address, from, to int64, params Query) (OpList, error) {
+	var (
+		list   OpList
+		cursor uint64
+	)
+	for {
+		q := params.Clone().
+			Asc().
+			WithLimit(ExportPageSize).
+			AndArg("since", from-1).
+			AndArg("until", to)
+		if cursor > 0 {
+			q = q.WithCursor(cursor)
+		}
+		calls, err := c.ListCalls(ctx, addr, q)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, calls...)
+		if uint(len(calls)) < ExportPageSize {
+			return list, nil
+		}
+		cursor = calls.Cursor()
+	}
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
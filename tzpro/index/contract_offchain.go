@@ -0,0 +1,150 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"context"
+	"fmt"
+
+	"blockwatch.cc/tzgo/micheline"
+)
+
+// OffchainView is a TZIP-16 off-chain view implemented in Michelson.
+type OffchainView struct {
+	Name        string
+	Description string
+	Pure        bool
+	Param       Type // invalid when the view takes no parameter
+	Retval      Type
+	Code        Prim
+}
+
+// OffchainViews parses TZIP-16 off-chain views with a Michelson storage
+// implementation from contract metadata meta, e.g. loaded with
+// MetadataAPI.GetWallet. Views implemented only as REST API queries are
+// skipped. Without TZIP-16 metadata the list is empty.
+func (s ContractScript) OffchainViews(meta *Metadata) ([]OffchainView, error) {
+	if meta == nil || !meta.Has("tz16") {
+		return nil, nil
+	}
+	list := make([]OffchainView, 0)
+	for _, v := range meta.Tz16().Views {
+		for _, impl := range v.Implementations {
+			if impl.Storage == nil {
+				continue
+			}
+			if !impl.Storage.ReturnType.IsValid() {
+				return nil, fmt.Errorf("offchain view %s: missing return type", v.Name)
+			}
+			if !impl.Storage.Code.IsValid() {
+				return nil, fmt.Errorf("offchain view %s: missing code", v.Name)
+			}
+			view := OffchainView{
+				Name:        v.Name,
+				Description: v.Description,
+				Pure:        v.Pure,
+				Retval:      micheline.NewType(impl.Storage.ReturnType),
+				Code:        impl.Storage.Code,
+			}
+			if impl.Storage.ParamType.IsValid() {
+				view.Param = micheline.NewType(impl.Storage.ParamType)
+			}
+			list = append(list, view)
+			break
+		}
+	}
+	return list, nil
+}
+
+// runCodeRequest is the body of the node RPC run_code call.
+type runCodeRequest struct {
+	ChainId ChainIdHash    `json:"chain_id"`
+	Script  micheline.Code `json:"script"`
+	Storage Prim           `json:"storage"`
+	Input   Prim           `json:"input"`
+	Amount  string         `json:"amount"`
+	Balance string         `json:"balance"`
+}
+
+type runCodeResponse struct {
+	Storage Prim `json:"storage"`
+}
+
+// RunOffchainView evaluates off-chain view of contract addr with args
+// against the current storage and returns the result decoded with the
+// view's return type. Args are validated against the view's parameter
+// type. The view code runs on the node RPC of the client wrapped into a
+// temporary script, so context instructions like SELF, BALANCE or AMOUNT
+// do not refer to addr.
+func (c *contractClient) RunOffchainView(ctx context.Context, addr Address, view OffchainView, args map[string]any) (ContractValue, error) {
+	rpc, chain, err := c.nodeRpc(ctx)
+	if err != nil {
+		return ContractValue{}, err
+	}
+	input, err := marshalViewArgs(view.Name, view.Param, args)
+	if err != nil {
+		return ContractValue{}, err
+	}
+	script, err := c.LoadScript(ctx, addr)
+	if err != nil {
+		return ContractValue{}, err
+	}
+	if script.Script == nil || len(script.Script.Code.Storage.Args) == 0 {
+		return ContractValue{}, ErrNoType
+	}
+	store, err := c.GetStorage(ctx, addr, NewQuery().WithPrim())
+	if err != nil {
+		return ContractValue{}, err
+	}
+	if store.Prim == nil {
+		return ContractValue{}, ErrNoStorage
+	}
+
+	// wrap view code into a script with parameter (pair param storage)
+	// which stores the view result as option
+	paramType := micheline.NewCode(micheline.T_UNIT)
+	code := micheline.NewSeq(micheline.NewCode(micheline.I_CAR))
+	if view.Param.IsValid() {
+		paramType = view.Param.Prim
+	} else {
+		code.Args = append(code.Args, micheline.NewCode(micheline.I_CDR))
+	}
+	code.Args = append(code.Args,
+		view.Code,
+		micheline.NewCode(micheline.I_SOME),
+		micheline.NewCode(micheline.I_NIL, micheline.NewPrim(micheline.T_OPERATION)),
+		micheline.NewCode(micheline.I_PAIR),
+	)
+	req := runCodeRequest{
+		ChainId: chain,
+		Script: micheline.Code{
+			Param: micheline.NewCode(micheline.K_PARAMETER,
+				micheline.NewPairType(paramType, script.Script.Code.Storage.Args[0]),
+			),
+			Storage: micheline.NewCode(micheline.K_STORAGE,
+				micheline.NewCode(micheline.T_OPTION, view.Retval.Prim),
+			),
+			Code: micheline.NewCode(micheline.K_CODE, code),
+		},
+		Input:   micheline.NewPair(input, *store.Prim),
+		Storage: micheline.NewCode(micheline.D_NONE),
+		Amount:  "0",
+		Balance: "0",
+	}
+	var resp runCodeResponse
+	u := "/chains/main/blocks/head/helpers/scripts/run_code"
+	if err := rpc.Post(ctx, u, nil, req, &resp); err != nil {
+		return ContractValue{}, err
+	}
+	if resp.Storage.OpCode != micheline.D_SOME || len(resp.Storage.Args) == 0 {
+		return ContractValue{}, fmt.Errorf("offchain view %s: missing result", view.Name)
+	}
+	data := resp.Storage.Args[0]
+	val := NewValue(view.Retval, data)
+	m, err := val.Map()
+	if err != nil {
+		return ContractValue{}, fmt.Errorf("offchain view %s: %w", view.Name, err)
+	}
+	return ContractValue{Value: m, Prim: &data}, nil
+}
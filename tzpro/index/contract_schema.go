@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"blockwatch.cc/tzgo/micheline"
+)
+
+// EntrypointJSONSchema converts the parameter type of entrypoint name
+// into a JSON Schema document describing values accepted by
+// BuildParameters.
+func (s ContractScript) EntrypointJSONSchema(name string) (json.RawMessage, error) {
+	eps := s.Entrypoints
+	if len(eps) == 0 && s.Script != nil {
+		eps, _ = s.Script.Entrypoints(true)
+	}
+	ep, ok := eps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown entrypoint %q", name)
+	}
+	var schema map[string]any
+	switch len(ep.Typedef) {
+	case 0:
+		schema = map[string]any{"type": "null"}
+	case 1:
+		schema = typedefSchema(ep.Typedef[0])
+	default:
+		schema = typedefSchema(Typedef{Type: micheline.TypeStruct, Args: ep.Typedef})
+	}
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = name
+	return json.Marshal(schema)
+}
+
+func typedefSchema(t Typedef) map[string]any {
+	schema := make(map[string]any)
+	switch t.Type {
+	case "nat", "mutez":
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+	case "int":
+		schema["type"] = "integer"
+	case "string":
+		schema["type"] = "string"
+	case "address", "contract":
+		schema["type"] = "string"
+		schema["format"] = "tezos-address"
+	case "key_hash":
+		schema["type"] = "string"
+		schema["format"] = "tezos-key-hash"
+	case "key":
+		schema["type"] = "string"
+		schema["format"] = "tezos-key"
+	case "signature":
+		schema["type"] = "string"
+		schema["format"] = "tezos-signature"
+	case "chain_id":
+		schema["type"] = "string"
+		schema["format"] = "tezos-chain-id"
+	case "timestamp":
+		schema["type"] = "string"
+		schema["format"] = "date-time"
+	case "bytes":
+		schema["type"] = "string"
+		schema["pattern"] = "^([0-9a-fA-F]{2})*$"
+	case "bool":
+		schema["type"] = "boolean"
+	case "unit":
+		schema["type"] = "null"
+	case "list", "set":
+		schema["type"] = "array"
+		if len(t.Args) > 0 {
+			schema["items"] = typedefSchema(unnamed(t.Args[0]))
+		}
+		if t.Type == "set" {
+			schema["uniqueItems"] = true
+		}
+	case "map", "big_map":
+		schema["type"] = "object"
+		if len(t.Args) > 1 {
+			schema["additionalProperties"] = typedefSchema(unnamed(t.Args[1]))
+		}
+	case micheline.TypeStruct:
+		props := make(map[string]any)
+		required := make([]string, 0)
+		for _, v := range t.Args {
+			props[v.Name] = typedefSchema(v)
+			if !v.Optional {
+				required = append(required, v.Name)
+			}
+		}
+		schema["type"] = "object"
+		schema["properties"] = props
+		schema["required"] = required
+		schema["additionalProperties"] = false
+	case micheline.TypeUnion:
+		branches := make([]any, 0, len(t.Args))
+		for _, v := range t.Args {
+			branches = append(branches, map[string]any{
+				"type":                 "object",
+				"properties":           map[string]any{v.Name: typedefSchema(v)},
+				"required":             []string{v.Name},
+				"additionalProperties": false,
+			})
+		}
+		schema["oneOf"] = branches
+	default:
+		// lambda, ticket, sapling and other types accept any JSON value
+		schema["description"] = t.Type
+	}
+	if t.Name != "" && t.Name[0] != '@' {
+		schema["title"] = t.Name
+	}
+	if t.Optional {
+		return map[string]any{
+			"anyOf": []any{schema, map[string]any{"type": "null"}},
+		}
+	}
+	return schema
+}
+
+func unnamed(t Typedef) Typedef {
+	t.Name = ""
+	return t
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
+)
+
+// StoragePoint is a single sample of a contract's paid storage series.
+// StoragePaid is the total number of storage bytes paid for the contract
+// up to Time, i.e. the high-water mark of its storage size.
+type StoragePoint struct {
+	Time        time.Time `json:"time"`
+	StoragePaid int64     `json:"storage_paid"`
+}
+
+// storageDelta is a row of the op series, i.e. storage paid by operations
+// received in one interval.
+type storageDelta struct {
+	Time        time.Time `json:"time"`
+	StoragePaid int64     `json:"storage_paid"`
+}
+
+// GetStorageSeries returns paid storage of contract addr over time. Params
+// select the aggregation interval, the time range and a limit on the
+// number of points. Each point carries the total at the end of its
+// interval. The series is derived from the op series of storage paid by
+// operations received by addr from the start of the range until now, and
+// the contract's current total. Columns are fixed and fill modes other than
+// none and last are rejected since gaps in a cumulative series can only
+// repeat the last value.
+func (c *contractClient) GetStorageSeries(ctx context.Context, addr Address, params SeriesParams) ([]StoragePoint, error) {
+	if err := client.CheckAddress(addr); err != nil {
+		return nil, err
+	}
+	if err := params.Check(); err != nil {
+		return nil, err
+	}
+	if len(params.Columns) > 0 {
+		return nil, fmt.Errorf("storage series: columns are not supported")
+	}
+	fill := client.FillModeNone
+	switch params.Fill {
+	case client.FillModeInvalid, client.FillModeNone:
+	case client.FillModeLast:
+		// empty intervals have no storage paid, so their total repeats
+		fill = client.FillModeZero
+	default:
+		return nil, fmt.Errorf("storage series: fill mode %q is not supported", params.Fill)
+	}
+	cc, err := c.Get(ctx, addr, NewQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	// load deltas until now to derive totals from the current total,
+	// limit and end of range are applied below
+	cols := []string{"time", "storage_paid"}
+	q := client.NewSeriesParams().
+		WithCollapse(params.Collapse).
+		WithRange(params.From, time.Time{}).
+		WithFill(fill).
+		WithColumns(cols...).
+		Query("/series/op").
+		AndEqual("receiver", addr)
+	var data json.RawMessage
+	if err := c.client.GetQuery(ctx, q, nil, &data); err != nil {
+		return nil, err
+	}
+	deltas := make([]storageDelta, 0)
+	if err := client.DecodeSlice(data, cols, &deltas); err != nil {
+		return nil, err
+	}
+
+	// walk backwards from the current total
+	total := cc.StoragePaid
+	points := make([]StoragePoint, len(deltas))
+	for i := len(deltas) - 1; i >= 0; i-- {
+		points[i] = StoragePoint{Time: deltas[i].Time, StoragePaid: total}
+		total -= deltas[i].StoragePaid
+	}
+	if !params.To.IsZero() {
+		n := sort.Search(len(points), func(i int) bool { return points[i].Time.After(params.To) })
+		points = points[:n]
+	}
+	if params.Limit > 0 && len(points) > params.Limit {
+		points = points[:params.Limit]
+	}
+	return points, nil
+}
+
+// StorageGrowth returns the change in paid storage between the first and
+// the last point of a series.
+func StorageGrowth(points []StoragePoint) int64 {
+	if len(points) == 0 {
+		return 0
+	}
+	return points[len(points)-1].StoragePaid - points[0].StoragePaid
+}
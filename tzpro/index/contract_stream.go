@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"context"
+	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+// SubscribeContractCalls streams new calls of contract addr. It is not
+// push based: the subscription polls ListCalls every 10 seconds with
+// params and a cursor, starting at the most recent call. After errors it
+// resumes from the last delivered op, backing off exponentially up to 5
+// minutes. Errors are reported on the error channel without ending the
+// subscription. Both channels are closed when ctx is canceled.
+func (c *contractClient) SubscribeContractCalls(ctx context.Context, addr Address, params Query) (<-chan *Op, <-chan error) {
+	ops := make(chan *Op)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(ops)
+		var (
+			cursor  uint64
+			backoff time.Duration
+			started bool
+		)
+		for {
+			var (
+				calls OpList
+				err   error
+			)
+			if !started {
+				calls, err = c.ListCalls(ctx, addr, params.Clone().Desc().WithLimit(1))
+				if err == nil {
+					cursor = calls.Cursor()
+					started = true
+					calls = nil
+				}
+			} else {
+				calls, err = c.ListCalls(ctx, addr, params.Clone().Asc().WithCursor(cursor))
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case errs <- err:
+				default:
+				}
+				wait := util.Backoff(backoff, util.MaxBackoff)
+				if e, ok := client.IsErrRateLimited(err); ok && e.Deadline() > wait {
+					wait = e.Deadline()
+				}
+				backoff = wait
+				if !util.Sleep(ctx, wait) {
+					return
+				}
+				continue
+			}
+			backoff = 0
+			for _, op := range calls {
+				select {
+				case ops <- op:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(calls) > 0 {
+				cursor = calls.Cursor()
+				continue
+			}
+			if !util.Sleep(ctx, util.PollInterval) {
+				return
+			}
+		}
+	}()
+	return ops, errs
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"blockwatch.cc/tzgo/micheline"
+	"blockwatch.cc/tzpro-go/internal/client"
+)
+
+// TestContractTableExplorerConsistency decodes the same contract from a
+// table reply with hex encoded script and storage and from an explorer
+// reply and checks that all table columns are populated alike.
+func TestContractTableExplorerConsistency(t *testing.T) {
+	script := micheline.NewScript()
+	script.Code.Param = micheline.NewCode(micheline.K_PARAMETER, micheline.NewPrim(micheline.T_UNIT))
+	script.Code.Storage = micheline.NewCode(micheline.K_STORAGE, micheline.NewPrim(micheline.T_NAT))
+	script.Code.Code = micheline.NewCode(micheline.K_CODE, micheline.NewSeq(
+		micheline.NewCode(micheline.I_CDR),
+		micheline.NewCode(micheline.I_NIL, micheline.NewPrim(micheline.T_OPERATION)),
+		micheline.NewCode(micheline.I_PAIR),
+	))
+	script.Storage = micheline.NewInt64(42)
+	scriptBin, err := script.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageBin, err := script.Storage.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptJson, err := json.Marshal(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storageJson, err := json.Marshal(script.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const addr = "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5"
+	cols := []string{"row_id", "address", "storage_size", "script", "storage"}
+	table := fmt.Sprintf(`[[7,%q,17,%q,%q]]`, addr, hex.EncodeToString(scriptBin), hex.EncodeToString(storageBin))
+	explorer := fmt.Sprintf(`{"row_id":7,"address":%q,"storage_size":17,"script":%s,"storage":%s,"baker":%q}`,
+		addr, scriptJson, storageJson, addr)
+
+	rows := make([]*Contract, 0)
+	if err := client.DecodeSlice([]byte(table), cols, &rows); err != nil {
+		t.Fatalf("table decode: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	var ex Contract
+	if err := json.Unmarshal([]byte(explorer), &ex); err != nil {
+		t.Fatalf("explorer decode: %v", err)
+	}
+
+	tc := rows[0]
+	if tc.RowId != ex.RowId || !tc.Address.Equal(ex.Address) || tc.StorageSize != ex.StorageSize {
+		t.Errorf("scalar columns differ: table %+v, explorer %+v", tc, ex)
+	}
+	for _, c := range []struct {
+		name        string
+		table, expl any
+	}{
+		{"script", tc.Script, ex.Script},
+		{"storage", tc.Storage, ex.Storage},
+	} {
+		tj, _ := json.Marshal(c.table)
+		ej, _ := json.Marshal(c.expl)
+		if string(tj) != string(ej) {
+			t.Errorf("%s differs: table %s, explorer %s", c.name, tj, ej)
+		}
+	}
+	if tc.Baker.IsValid() {
+		t.Error("explorer-only baker populated from table")
+	}
+}
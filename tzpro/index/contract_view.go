@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"context"
+	"fmt"
+
+	"blockwatch.cc/tzgo/micheline"
+	"blockwatch.cc/tzpro-go/internal/client"
+)
+
+// View returns the on-chain view name.
+func (s ContractScript) View(name string) (micheline.View, bool) {
+	views := s.Views
+	if len(views) == 0 && s.Script != nil {
+		views, _ = s.Script.Views(false, false)
+	}
+	v, ok := views[name]
+	return v, ok
+}
+
+// GetViews returns names, parameter and return types of on-chain
+// views of contract addr without view code. There is no dedicated views
+// endpoint, so views are read from the script cache which loads the full
+// script once.
+func (c *contractClient) GetViews(ctx context.Context, addr Address) (Views, error) {
+	script, err := c.LoadScript(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	// copy so callers cannot modify the cached script
+	views := make(Views, len(script.Views))
+	for n, v := range script.Views {
+		views[n] = v
+	}
+	return views, nil
+}
+
+// BuildViewInput marshals named Go values in args into a Micheline value
+// matching the parameter type of on-chain view name. Values are matched
+// by annotation name like in BuildParameters. A view with a single
+// unnamed parameter takes its value from args[""] or the only entry.
+func (s ContractScript) BuildViewInput(name string, args map[string]any) (Prim, error) {
+	view, ok := s.View(name)
+	if !ok {
+		return Prim{}, fmt.Errorf("unknown view %q", name)
+	}
+	return marshalViewArgs(name, view.Param, args)
+}
+
+// marshalViewArgs marshals args into a value of parameter type param of
+// view name. An invalid type takes no arguments and yields unit.
+func marshalViewArgs(name string, param Type, args map[string]any) (Prim, error) {
+	if !param.IsValid() {
+		return micheline.NewCode(micheline.D_UNIT), nil
+	}
+	typ := param.Typedef("")
+	switch {
+	case typ.OpCode() == micheline.T_UNIT:
+		return micheline.NewCode(micheline.D_UNIT), nil
+	case typ.Type == micheline.TypeStruct:
+		if err := checkArgs(typ.Args, args); err != nil {
+			return Prim{}, fmt.Errorf("view %s: %w", name, err)
+		}
+	case typ.Name == "" && len(args) == 1:
+		for _, v := range args {
+			args = map[string]any{"": v}
+		}
+	default:
+		if _, ok := args[typ.Name]; !ok && !typ.Optional {
+			return Prim{}, fmt.Errorf("view %s: missing arg %q", name, typ.Name)
+		}
+	}
+	prim, err := typ.Marshal(args, true)
+	if err != nil {
+		return Prim{}, fmt.Errorf("view %s: %w", name, err)
+	}
+	return prim, nil
+}
+
+// runViewRequest is the body of the node RPC run_script_view call.
+type runViewRequest struct {
+	Contract     Address     `json:"contract"`
+	View         string      `json:"view"`
+	Input        Prim        `json:"input"`
+	ChainId      ChainIdHash `json:"chain_id"`
+	UnlimitedGas bool        `json:"unlimited_gas"`
+	Mode         string      `json:"unparsing_mode"`
+}
+
+type runViewResponse struct {
+	Data Prim `json:"data"`
+}
+
+// RunView executes on-chain view name of contract addr with args at the
+// current head and returns the result decoded with the view's return
+// type. Args are validated against the view's parameter type before the
+// call. The API has no view endpoint, so views run on the node RPC of the
+// client, by default the TzPro RPC service.
+func (c *contractClient) RunView(ctx context.Context, addr Address, name string, args map[string]any) (ContractValue, error) {
+	rpc, chain, err := c.nodeRpc(ctx)
+	if err != nil {
+		return ContractValue{}, err
+	}
+	script, err := c.LoadScript(ctx, addr)
+	if err != nil {
+		return ContractValue{}, err
+	}
+	input, err := script.BuildViewInput(name, args)
+	if err != nil {
+		return ContractValue{}, err
+	}
+	req := runViewRequest{
+		Contract:     addr,
+		View:         name,
+		Input:        input,
+		ChainId:      chain,
+		UnlimitedGas: true,
+		Mode:         "Readable",
+	}
+	var resp runViewResponse
+	u := "/chains/main/blocks/head/helpers/scripts/run_script_view"
+	if err := rpc.Post(ctx, u, nil, req, &resp); err != nil {
+		return ContractValue{}, err
+	}
+	res := ContractValue{Prim: &resp.Data}
+	if view, _ := script.View(name); view.Retval.IsValid() {
+		val := NewValue(view.Retval, resp.Data)
+		res.Value, err = val.Map()
+		if err != nil {
+			return ContractValue{}, fmt.Errorf("view %s: %w", name, err)
+		}
+	}
+	return res, nil
+}
+
+// nodeRpc returns the node RPC client and the chain id of the indexed
+// network.
+func (c *contractClient) nodeRpc(ctx context.Context) (*client.Client, ChainIdHash, error) {
+	rpc := c.client.Rpc()
+	if rpc == nil {
+		return nil, ChainIdHash{}, ErrNoRpc
+	}
+	tip, err := NewExplorerAPI(c.client).GetTip(ctx)
+	if err != nil {
+		return nil, ChainIdHash{}, err
+	}
+	return rpc, tip.ChainId, nil
+}
@@ -22,6 +22,10 @@ type Event struct {
 	TypeHash string  `json:"type_hash"`
 }
 
+func (e *Event) GetRowId() uint64 {
+	return e.RowId
+}
+
 type EventQuery = client.TableQuery[*Event]
 
 func (a contractClient) NewEventQuery() *EventQuery {
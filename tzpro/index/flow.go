@@ -34,6 +34,10 @@ type Flow struct {
     TokenAge       int64     `json:"token_age"`
 }
 
+func (f *Flow) GetRowId() uint64 {
+    return f.Id
+}
+
 type FlowQuery = client.TableQuery[*Flow]
 
 func (a accountClient) NewFlowQuery() *FlowQuery {
@@ -53,6 +53,10 @@ type Income struct {
 	EndTime                time.Time `json:"end_time"`   // table only
 }
 
+func (i *Income) GetRowId() uint64 {
+	return i.RowId
+}
+
 type IncomeQuery = client.TableQuery[*Income]
 
 func (c *bakerClient) NewIncomeQuery() *IncomeQuery {
@@ -18,6 +18,7 @@ import (
 
 type MetadataAPI interface {
 	List(context.Context) ([]Metadata, error)
+	ListQuery(context.Context, Query) ([]Metadata, error)
 	GetWallet(context.Context, Address) (Metadata, error)
 	Create(context.Context, []Metadata) ([]Metadata, error)
 	Update(context.Context, Metadata) (Metadata, error)
@@ -110,6 +111,22 @@ func (m *Metadata) Delete(name string) {
 	}
 }
 
+// Name returns the alias name or an empty string when no alias is defined.
+func (m Metadata) Name() string {
+	if a, ok := m.Contents["alias"].(*AliasMetadata); ok && a != nil {
+		return a.Name
+	}
+	return ""
+}
+
+// Tags returns alias tags if defined.
+func (m Metadata) Tags() []string {
+	if a, ok := m.Contents["alias"].(*AliasMetadata); ok && a != nil {
+		return a.Tags
+	}
+	return nil
+}
+
 func (m Metadata) IsEmpty() bool {
 	return len(m.Contents) == 0
 }
@@ -121,9 +138,14 @@ func (m Metadata) Clone() Metadata {
 	return clone
 }
 
+// Merge returns a deep copy of m where models present in d replace
+// models in m. Neither m nor d are modified.
 func (m Metadata) Merge(d Metadata) Metadata {
-	md := m
-	for n, v := range d.Contents {
+	md := m.Clone()
+	if md.Contents == nil {
+		md.Contents = make(map[string]any)
+	}
+	for n, v := range d.Clone().Contents {
 		if v == nil {
 			continue
 		}
@@ -132,6 +154,26 @@ func (m Metadata) Merge(d Metadata) Metadata {
 	return md
 }
 
+// Fill returns a deep copy of m where models missing in m are taken
+// from d. Models already present in m always take precedence, which
+// allows overlaying user-defined metadata on top of indexer data.
+func (m Metadata) Fill(d Metadata) Metadata {
+	md := m.Clone()
+	if !md.Address.IsValid() {
+		md.Address = d.Address
+	}
+	if md.Contents == nil {
+		md.Contents = make(map[string]any)
+	}
+	for n, v := range d.Clone().Contents {
+		if v == nil || md.Has(n) {
+			continue
+		}
+		md.Contents[n] = v
+	}
+	return md
+}
+
 func (m Metadata) MarshalJSON() ([]byte, error) {
 	out := make(map[string]any)
 	for n, v := range m.Contents {
@@ -478,6 +520,15 @@ func (c *metaClient) List(ctx context.Context) ([]Metadata, error) {
 	return resp, nil
 }
 
+func (c *metaClient) ListQuery(ctx context.Context, params Query) ([]Metadata, error) {
+	resp := make([]Metadata, 0)
+	q := params.WithPath("/metadata")
+	if err := c.client.GetQuery(ctx, q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (c *metaClient) GetWallet(ctx context.Context, addr Address) (Metadata, error) {
 	var resp Metadata
 	if err := c.client.Get(ctx, "/metadata/"+addr.String(), nil, &resp); err != nil {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -123,6 +124,10 @@ type Op struct {
 	bigmaps map[int64]Type // optional, may be decoded from script
 }
 
+func (o *Op) GetRowId() uint64 {
+	return o.Id
+}
+
 func (o *Op) BlockId() BlockId {
 	return BlockId{
 		Height: o.Height,
@@ -151,6 +156,44 @@ func (o *Op) Content() []*Op {
 	return list
 }
 
+// Internals returns all internal operations emitted by o and its batch
+// contents in execution order. Internal ops keep the OpN and OpP position
+// of the originating op.
+func (o *Op) Internals() []*Op {
+	list := make([]*Op, 0)
+	o.WalkInternal(func(op *Op) error {
+		list = append(list, op)
+		return nil
+	})
+	return list
+}
+
+// WalkInternal calls fn for each internal operation in execution order.
+// Returning ErrStopWalk from fn stops the walk without error.
+func (o *Op) WalkInternal(fn func(*Op) error) error {
+	if err := o.walkInternal(fn); err != nil && !errors.Is(err, ErrStopWalk) {
+		return err
+	}
+	return nil
+}
+
+func (o *Op) walkInternal(fn func(*Op) error) error {
+	for _, v := range o.Batch {
+		if err := v.walkInternal(fn); err != nil {
+			return err
+		}
+	}
+	for _, v := range o.Internal {
+		if err := fn(v); err != nil {
+			return err
+		}
+		if err := v.walkInternal(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (o *Op) Addresses() *AddressSet {
 	set := NewAddressSet()
 	for _, op := range o.Content() {
@@ -212,6 +255,35 @@ func (o Op) Costs() Costs {
 	}
 }
 
+// OpCost is the total cost of an operation with a breakdown per content.
+type OpCost struct {
+	Costs
+	Contents []Costs // one entry per batch content, each including its nested internal results
+}
+
+// Cost sums fees, gas, storage and burn of o including all nested
+// internal results. Batch operations are summed from their contents; the
+// batch wrapper itself is not counted.
+func (o *Op) Cost() OpCost {
+	contents := o.Batch
+	if len(contents) == 0 {
+		contents = []*Op{o}
+	}
+	res := OpCost{
+		Contents: make([]Costs, 0, len(contents)),
+	}
+	for _, v := range contents {
+		c := v.Costs()
+		_ = v.WalkInternal(func(in *Op) error {
+			c = c.Add(in.Costs())
+			return nil
+		})
+		res.Contents = append(res.Contents, c)
+		res.Costs = res.Costs.Add(c)
+	}
+	return res
+}
+
 func (o Op) HasParameters() bool {
 	return len(o.Parameters) > 0
 }
@@ -300,8 +372,11 @@ func (o Op) DecodeStoragePrim(noFail bool) (prim Prim, err error) {
 		return
 	default:
 		cv := &ContractValue{}
-		err := json.Unmarshal(o.Storage, cv)
-		return *cv.Prim, err
+		if err = json.Unmarshal(o.Storage, cv); err != nil {
+			return
+		}
+		prim, _ = cv.AsPrim()
+		return
 	}
 }
 
@@ -467,30 +542,37 @@ func (og OpList) Costs() Costs {
 	return c
 }
 
-type OpList []*Op
+// OpList is a client.List of operations. It is a distinct type to carry
+// the Costs method.
+type OpList client.List[*Op]
 
 func (l OpList) Len() int {
-	return len(l)
+	return client.List[*Op](l).Len()
 }
 
 func (l OpList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].Id
+	return client.List[*Op](l).Cursor()
 }
 
+func (l *OpList) UnmarshalJSON(buf []byte) error {
+	return (*client.List[*Op])(l).UnmarshalJSON(buf)
+}
+
+// ResolveTypes loads contract type info required to decode parameters,
+// storage and bigmap updates for ops including batch and internal contents.
 func (c opClient) ResolveTypes(ctx context.Context, ops ...*Op) error {
-	for _, op := range ops {
-		if !op.IsContract || !op.Receiver.IsContract() {
-			continue
-		}
-		// load contract type info (required for decoding storage/param data)
-		script, err := c.loadScript(ctx, op.Receiver)
-		if err != nil {
-			return err
+	for _, v := range ops {
+		for _, op := range v.Content() {
+			if !op.IsContract || !op.Receiver.IsContract() {
+				continue
+			}
+			// load contract type info (required for decoding storage/param data)
+			script, err := c.loadScript(ctx, op.Receiver)
+			if err != nil {
+				return err
+			}
+			op.WithScript(script)
 		}
-		op.WithScript(script)
 	}
 	return nil
 }
@@ -501,10 +583,12 @@ func (c opClient) NewQuery() *OpQuery {
 	return client.NewTableQuery[*Op](c.client, "op")
 }
 
+// Get returns all contents of the operation group with hash. Call
+// ResolveTypes on the result before decoding parameters or storage.
 func (c opClient) Get(ctx context.Context, hash OpHash, params Query) (OpList, error) {
 	o := make(OpList, 0)
-	u := params.WithPath(fmt.Sprintf("/explorer/op/%s", hash)).Url()
-	if err := c.client.Get(ctx, u, nil, &o); err != nil {
+	q := params.WithPath(fmt.Sprintf("/explorer/op/%s", hash))
+	if err := c.client.GetQuery(ctx, q, nil, &o); err != nil {
 		return nil, err
 	}
 	return o, nil
@@ -0,0 +1,109 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"strconv"
+
+	"blockwatch.cc/tzpro-go/internal/util"
+)
+
+// StorageChange is a single path-level change caused by an operation.
+// Storage fields use dotted paths like ContractValue getters. Bigmap
+// changes use the bigmap name (or bigmap_<id>) followed by the key.
+type StorageChange struct {
+	Path     string
+	Action   DiffAction     // update or remove for values, alloc or copy for bigmaps
+	BigmapId int64          // zero for storage fields
+	Old      *ContractValue // nil when previously absent or unknown
+	New      *ContractValue // nil on removal
+}
+
+// StorageDiff decodes bigmap updates of o using types from script and
+// returns them as path-level changes. Operations do not carry storage
+// from before execution, use StorageDiffFrom to include storage fields.
+func (o *Op) StorageDiff(script *ContractScript) ([]StorageChange, error) {
+	return o.StorageDiffFrom(script, nil)
+}
+
+// StorageDiffFrom works like StorageDiff and additionally compares storage
+// after execution of o against prev, e.g. storage returned by the previous
+// call. Changes are sorted by path, bigmap changes keep their diff order.
+func (o *Op) StorageDiffFrom(script *ContractScript, prev *ContractValue) ([]StorageChange, error) {
+	if script == nil {
+		return nil, ErrNoType
+	}
+	op := *o
+	op.WithScript(script)
+
+	changes := make([]StorageChange, 0)
+	if prev != nil && op.HasStorage() {
+		next, err := op.DecodeStorage(false, 0)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, diffValueLeaves(prev.Value, next.Value)...)
+	}
+
+	if op.HasBigmapUpdates() {
+		updates, err := op.DecodeBigmapUpdates(false, false, 0)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[int64]string, len(script.BigmapNames))
+		for n, id := range script.BigmapNames {
+			names[id] = n
+		}
+		for _, upd := range updates {
+			name, ok := names[upd.BigmapId]
+			if !ok {
+				name = "bigmap_" + strconv.FormatInt(upd.BigmapId, 10)
+			}
+			c := StorageChange{
+				Path:     name,
+				Action:   upd.Action,
+				BigmapId: upd.BigmapId,
+			}
+			switch upd.Action {
+			case DiffActionUpdate:
+				c.Path += "." + upd.Key.String()
+				c.New = &ContractValue{Value: upd.Value, Prim: upd.ValuePrim}
+			case DiffActionRemove:
+				if upd.Key.Len() > 0 {
+					c.Path += "." + upd.Key.String()
+				}
+			}
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
+
+// diffValueLeaves compares leaf values of two decoded storage trees.
+func diffValueLeaves(prev, next any) []StorageChange {
+	diff := DiffContractValues(ContractValue{Value: prev}, ContractValue{Value: next})
+	changes := make([]StorageChange, 0, len(diff))
+	for _, d := range diff {
+		c := StorageChange{Path: d.Path, Action: DiffActionUpdate}
+		if d.Kind != ValueAdded {
+			c.Old = &ContractValue{Value: d.Old}
+		}
+		if d.Kind == ValueRemoved {
+			c.Action = DiffActionRemove
+		} else {
+			c.New = &ContractValue{Value: d.New}
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+func flattenValue(val any) map[string]any {
+	m := make(map[string]any)
+	_ = util.WalkValueMap("", val, func(path string, v any) error {
+		m[path] = v
+		return nil
+	})
+	return m
+}
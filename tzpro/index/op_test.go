@@ -0,0 +1,21 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package index
+
+import (
+	"testing"
+)
+
+func TestOpCostNestedInternal(t *testing.T) {
+	op := &Op{
+		GasUsed: 1,
+		Internal: []*Op{
+			{GasUsed: 10, Internal: []*Op{{GasUsed: 100}}},
+			{GasUsed: 1000},
+		},
+	}
+	if got := op.Cost().GasUsed; got != 1111 {
+		t.Errorf("got gas %d, want 1111", got)
+	}
+}
@@ -34,6 +34,10 @@ type Rights struct {
 	Seeded    util.HexBytes `json:"seeds_revealed"`
 }
 
+func (r *Rights) GetRowId() uint64 {
+	return r.RowId
+}
+
 func isSet(buf []byte, i int) bool {
 	if i < 0 || i >= len(buf)*8 {
 		return false
@@ -93,18 +97,7 @@ func (r Rights) RightAt(height int64, typ RightType) (Right, bool) {
 	return Right{}, false
 }
 
-type RightsList []*Rights
-
-func (l RightsList) Len() int {
-	return len(l)
-}
-
-func (l RightsList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].RowId
-}
+type RightsList = client.List[*Rights]
 
 type RightsQuery = client.TableQuery[*Rights]
 
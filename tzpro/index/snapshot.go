@@ -31,6 +31,10 @@ type StakeSnapshot struct {
 	SinceTime      time.Time `json:"since_time"`
 }
 
+func (s *StakeSnapshot) GetRowId() uint64 {
+	return s.RowId
+}
+
 type StakeSnapshotList []*Snapshot
 
 type StakeSnapshotQuery = client.TableQuery[*StakeSnapshot]
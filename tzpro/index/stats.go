@@ -38,8 +38,8 @@ type AgeReport struct {
 
 func (c *statsClient) GetAgeReport(ctx context.Context, params Query) ([]*AgeReport, error) {
 	rep := make([]*AgeReport, 0)
-	u := params.WithPath("/explorer/stats/age").Url()
-	if err := c.client.Get(ctx, u, nil, &rep); err != nil {
+	q := params.WithPath("/explorer/stats/age")
+	if err := c.client.GetQuery(ctx, q, nil, &rep); err != nil {
 		return nil, err
 	}
 	return rep, nil
@@ -67,8 +67,8 @@ type SupplyReport struct {
 
 func (c *statsClient) GetSupplyReport(ctx context.Context, params Query) ([]*SupplyReport, error) {
 	rep := make([]*SupplyReport, 0)
-	u := params.WithPath("/explorer/stats/supply").Url()
-	if err := c.client.Get(ctx, u, nil, &rep); err != nil {
+	q := params.WithPath("/explorer/stats/supply")
+	if err := c.client.GetQuery(ctx, q, nil, &rep); err != nil {
 		return nil, err
 	}
 	return rep, nil
@@ -88,8 +88,8 @@ type AccountsReport struct {
 
 func (c *statsClient) GetAccountsReport(ctx context.Context, params Query) ([]*AccountsReport, error) {
 	rep := make([]*AccountsReport, 0)
-	u := params.WithPath("/explorer/stats/sets").Url()
-	if err := c.client.Get(ctx, u, nil, &rep); err != nil {
+	q := params.WithPath("/explorer/stats/sets")
+	if err := c.client.GetQuery(ctx, q, nil, &rep); err != nil {
 		return nil, err
 	}
 	return rep, nil
@@ -126,8 +126,8 @@ type ActivityReport struct {
 
 func (c *statsClient) GetActivityReport(ctx context.Context, params Query) ([]*ActivityReport, error) {
 	rep := make([]*ActivityReport, 0)
-	u := params.WithPath("/explorer/stats/activity").Url()
-	if err := c.client.Get(ctx, u, nil, &rep); err != nil {
+	q := params.WithPath("/explorer/stats/activity")
+	if err := c.client.GetQuery(ctx, q, nil, &rep); err != nil {
 		return nil, err
 	}
 	return rep, nil
@@ -194,8 +194,8 @@ type BalanceReport struct {
 
 func (c *statsClient) GetBalanceReport(ctx context.Context, params Query) ([]*BalanceReport, error) {
 	rep := make([]*BalanceReport, 0)
-	u := params.WithPath("/explorer/stats/balance").Url()
-	if err := c.client.Get(ctx, u, nil, &rep); err != nil {
+	q := params.WithPath("/explorer/stats/balance")
+	if err := c.client.GetQuery(ctx, q, nil, &rep); err != nil {
 		return nil, err
 	}
 	return rep, nil
@@ -218,8 +218,8 @@ type OpReport struct {
 
 func (c *statsClient) GetOpReport(ctx context.Context, params Query) ([]*OpReport, error) {
 	rep := make([]*OpReport, 0)
-	u := params.WithPath("/explorer/stats/op").Url()
-	if err := c.client.Get(ctx, u, nil, &rep); err != nil {
+	q := params.WithPath("/explorer/stats/op")
+	if err := c.client.GetQuery(ctx, q, nil, &rep); err != nil {
 		return nil, err
 	}
 	return rep, nil
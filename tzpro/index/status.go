@@ -19,6 +19,19 @@ type Status struct {
 	Progress  float64 `json:"progress"`
 }
 
+// IsSynced returns true when the indexer has caught up with the chain.
+func (s Status) IsSynced() bool {
+	return s.Status == "synced"
+}
+
+// Lag returns the number of blocks the indexer is behind the node.
+func (s Status) Lag() int64 {
+	if s.Blocks <= s.Indexed {
+		return 0
+	}
+	return s.Blocks - s.Indexed
+}
+
 func (s *Status) UnmarshalJSON(data []byte) error {
 	if len(data) == 0 || bytes.Equal(data, []byte(`null`)) {
 		return nil
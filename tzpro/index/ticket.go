@@ -5,59 +5,17 @@ package index
 
 import (
 	"time"
+
+	"blockwatch.cc/tzpro-go/internal/client"
 )
 
 type (
-	TicketList        []*Ticket
-	TicketUpdateList  []*TicketUpdate
-	TicketBalanceList []*TicketBalance
-	TicketEventList   []*TicketEvent
+	TicketList        = client.List[*Ticket]
+	TicketUpdateList  = client.List[*TicketUpdate]
+	TicketBalanceList = client.List[*TicketBalance]
+	TicketEventList   = client.List[*TicketEvent]
 )
 
-func (l TicketList) Len() int {
-	return len(l)
-}
-
-func (l TicketList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].Id
-}
-
-func (l TicketUpdateList) Len() int {
-	return len(l)
-}
-
-func (l TicketUpdateList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].Id
-}
-
-func (l TicketBalanceList) Len() int {
-	return len(l)
-}
-
-func (l TicketBalanceList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].Id
-}
-
-func (l TicketEventList) Len() int {
-	return len(l)
-}
-
-func (l TicketEventList) Cursor() uint64 {
-	if len(l) == 0 {
-		return 0
-	}
-	return l[len(l)-1].Id
-}
-
 type TicketUpdate struct {
 	Id       uint64  `json:"id"`
 	Ticketer Address `json:"ticketer"`
@@ -67,6 +25,10 @@ type TicketUpdate struct {
 	Amount   Z       `json:"amount"`
 }
 
+func (t *TicketUpdate) GetRowId() uint64 {
+	return t.Id
+}
+
 type Ticket struct {
 	Id           uint64    `json:"id"`
 	Ticketer     Address   `json:"ticketer"`
@@ -85,6 +47,10 @@ type Ticket struct {
 	NumHolders   int       `json:"num_holders"`
 }
 
+func (t *Ticket) GetRowId() uint64 {
+	return t.Id
+}
+
 type TicketBalance struct {
 	Id           uint64    `json:"id"`
 	TicketId     uint64    `json:"-"               tzpro:"ticket"`
@@ -107,6 +73,10 @@ type TicketBalance struct {
 	VolBurn      Z         `json:"vol_burn"`
 }
 
+func (t *TicketBalance) GetRowId() uint64 {
+	return t.Id
+}
+
 type TicketEvent struct {
 	Id        uint64    `json:"id"`
 	TicketId  uint64    `json:"-"           tzpro:"ticket"`
@@ -122,3 +92,7 @@ type TicketEvent struct {
 	Time      time.Time `json:"time"`
 	OpId      uint64    `json:"op_id"`
 }
+
+func (t *TicketEvent) GetRowId() uint64 {
+	return t.Id
+}
@@ -40,6 +40,15 @@ type Tip struct {
 	Status             Status       `json:"status"`
 }
 
+// IndexedHeight returns the most recent block height that is safe to use
+// as upper bound in range queries.
+func (t Tip) IndexedHeight() int64 {
+	if t.Status.Indexed > 0 && t.Status.Indexed < t.Height {
+		return t.Status.Indexed
+	}
+	return t.Height
+}
+
 func (c *explorerClient) GetTip(ctx context.Context) (*Tip, error) {
 	tip := &Tip{}
 	if err := c.client.Get(ctx, "/explorer/tip", nil, tip); err != nil {
@@ -9,10 +9,13 @@ import (
 	"blockwatch.cc/tzgo/micheline"
 	"blockwatch.cc/tzgo/tezos"
 	"blockwatch.cc/tzpro-go/internal/client"
+	"blockwatch.cc/tzpro-go/internal/util"
 )
 
 type (
-	Query = client.Query
+	Query        = client.Query
+	SeriesParams = client.SeriesParams
+	BatchError   = client.BatchError[Address]
 
 	OpHash       = tezos.OpHash
 	OpStatus     = tezos.OpStatus
@@ -40,10 +43,12 @@ type (
 	Parameters   = micheline.Parameters
 	BigmapEvents = micheline.BigmapEvents
 	BigmapEvent  = micheline.BigmapEvent
+	WalkOptions  = util.WalkOptions
 )
 
 var (
 	NewQuery           = client.NewQuery
+	NewSeriesParams    = client.NewSeriesParams
 	NewAddressSet      = tezos.NewAddressSet
 	NewValue           = micheline.NewValue
 	NewType            = micheline.NewType
@@ -57,8 +62,12 @@ var (
 )
 
 var (
-	ErrNoStorage    = errors.New("no storage")
-	ErrNoParams     = errors.New("no parameters")
-	ErrNoBigmapDiff = errors.New("no bigmap diff")
-	ErrNoType       = errors.New("API type missing")
+	ErrNoStorage      = errors.New("no storage")
+	ErrNoParams       = errors.New("no parameters")
+	ErrNoBigmapDiff   = errors.New("no bigmap diff")
+	ErrNoType         = errors.New("API type missing")
+	ErrNoRpc          = errors.New("no node RPC configured")
+	ErrStopWalk       = util.ErrStopWalk
+	ErrInvalidAddress = client.ErrInvalidAddress
+	ErrNoData         = client.ErrNoData
 )
@@ -0,0 +1,56 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzpro
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"blockwatch.cc/tzgo/tezos"
+	"blockwatch.cc/tzpro-go/tzpro/index"
+)
+
+// ServerInfo describes the API server and the network it indexes.
+type ServerInfo struct {
+	Server   string             // server software as reported in HTTP headers
+	Network  string             // network name, e.g. Mainnet
+	ChainId  tezos.ChainIdHash  // network chain id
+	Protocol tezos.ProtocolHash // protocol at the indexed tip
+	Height   int64              // indexed tip height at the time of the call
+}
+
+type infoCache struct {
+	sync.Mutex
+	info *ServerInfo
+}
+
+// Ping checks the server is reachable and the indexer is responsive.
+func (s *Client) Ping(ctx context.Context) error {
+	_, err := s.Explorer.GetStatus(ctx)
+	return err
+}
+
+// ServerInfo returns information about the API server. The result is
+// fetched once and cached for the lifetime of the client.
+func (s *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	s.info.Lock()
+	defer s.info.Unlock()
+	if s.info.info != nil {
+		return s.info.info, nil
+	}
+	headers := make(http.Header)
+	tip := &index.Tip{}
+	if err := s.client.Get(ctx, "/explorer/tip", headers, tip); err != nil {
+		return nil, err
+	}
+	s.info.info = &ServerInfo{
+		Server:   headers.Get("Server"),
+		Network:  tip.Network,
+		ChainId:  tip.ChainId,
+		Protocol: tip.Protocol,
+		Height:   tip.Height,
+	}
+	return s.info.info, nil
+}
@@ -55,8 +55,8 @@ func (c *nftClient) GetMarket(ctx context.Context, addr Address) (*NftMarket, er
 
 func (c *nftClient) ListMarkets(ctx context.Context, params Query) ([]*NftMarket, error) {
 	list := make([]*NftMarket, 0)
-	u := params.WithPath("/v1/nft").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/nft")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
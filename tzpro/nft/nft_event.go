@@ -36,8 +36,8 @@ type NftEvent struct {
 
 func (c *nftClient) ListEvents(ctx context.Context, params Query) ([]*NftEvent, error) {
 	list := make([]*NftEvent, 0)
-	u := params.WithPath("/v1/nft/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/nft/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -45,8 +45,8 @@ func (c *nftClient) ListEvents(ctx context.Context, params Query) ([]*NftEvent,
 
 func (c *nftClient) ListMarketEvents(ctx context.Context, addr Address, params Query) ([]*NftEvent, error) {
 	list := make([]*NftEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/nft/%s/events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/nft/%s/events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -37,8 +37,8 @@ type NftPosition struct {
 
 func (c *nftClient) ListPositions(ctx context.Context, params Query) ([]*NftPosition, error) {
 	list := make([]*NftPosition, 0)
-	u := params.WithPath("/v1/nft/positions").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/nft/positions")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -46,8 +46,8 @@ func (c *nftClient) ListPositions(ctx context.Context, params Query) ([]*NftPosi
 
 func (c *nftClient) ListMarketPositions(ctx context.Context, addr Address, params Query) ([]*NftPosition, error) {
 	list := make([]*NftPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/nft/%s/positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/nft/%s/positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
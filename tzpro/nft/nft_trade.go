@@ -34,8 +34,8 @@ type NftTrade struct {
 
 func (c *nftClient) ListTrades(ctx context.Context, params Query) ([]*NftTrade, error) {
 	list := make([]*NftTrade, 0)
-	u := params.WithPath("/v1/nft/trades").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/nft/trades")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -43,8 +43,8 @@ func (c *nftClient) ListTrades(ctx context.Context, params Query) ([]*NftTrade,
 
 func (c *nftClient) ListMarketTrades(ctx context.Context, addr Address, params Query) ([]*NftTrade, error) {
 	list := make([]*NftTrade, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/nft/%s/trades", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/nft/%s/trades", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -4,11 +4,15 @@
 package tzpro
 
 import (
+	"context"
 	"crypto/tls"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
+	"blockwatch.cc/tzgo/tezos"
 	"blockwatch.cc/tzpro-go/internal/client"
 	"blockwatch.cc/tzpro-go/tzpro/defi"
 	"blockwatch.cc/tzpro-go/tzpro/identity"
@@ -29,6 +33,28 @@ var (
 	DefaultClient = NewClient("https://api.tzpro.io", nil)
 )
 
+// API is implemented by Client. Depend on it instead of *Client to swap
+// in a tzprotest.MockClient in tests. Builder methods (With...) and the
+// endpoint specific calls available through the interface-typed fields
+// of Client are not part of API. Mock endpoint calls with a client created
+// by tzprotest.MockTransport instead.
+type API interface {
+	Ping(ctx context.Context) error
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+	Do(ctx context.Context, method, path string, query url.Values, body io.Reader, result any) error
+	Endpoint() string
+	Metrics() Metrics
+	CacheStats() CacheStats
+	CacheGet(key Address) (any, bool)
+	CacheAdd(key Address, val any)
+	PurgeScript(addr Address)
+	UseScriptCache(cache *lru.TwoQueueCache[Address, any])
+	Retries() int
+	RetryDelay() time.Duration
+}
+
+var _ API = (*Client)(nil)
+
 type Client struct {
 	Account  index.AccountAPI
 	Block    index.BlockAPI
@@ -51,12 +77,24 @@ type Client struct {
 	// Zmq      zmq.ZmqAPI
 
 	client *client.Client
+	market *client.Client
+	ipfs   *client.Client
+	rpc    *client.Client
+	info   *infoCache
 }
 
 func NewClient(url string, httpClient *http.Client) *Client {
 	c := client.NewClient(url, httpClient).
 		WithApiKey(os.Getenv("TZPRO_API_KEY")).
 		WithUserAgent("tzpro-go/v" + SdkVersion)
+	ic := client.NewClient("https://ipfs.tzpro.io", httpClient).
+		WithApiKey(os.Getenv("TZPRO_API_KEY")).
+		WithUserAgent("tzpro-go/v" + SdkVersion).
+		WithTimeout(60 * time.Second)
+	rc := client.NewClient("https://rpc.tzpro.io", httpClient).
+		WithApiKey(os.Getenv("TZPRO_API_KEY")).
+		WithUserAgent("tzpro-go/v" + SdkVersion)
+	c.WithRpc(rc).WithIpfs(ic)
 
 	return &Client{
 		Account:  index.NewAccountAPI(c),
@@ -76,14 +114,13 @@ func NewClient(url string, httpClient *http.Client) *Client {
 		Profile:  identity.NewProfileAPI(c),
 		Wallet:   wallet.NewWalletAPI(c),
 		Market:   market.NewMarketAPI(c),
-		Ipfs: ipfs.NewIpfsAPI(
-			client.NewClient("https://ipfs.tzpro.io", httpClient).
-				WithApiKey(os.Getenv("TZPRO_API_KEY")).
-				WithUserAgent("tzpro-go/v" + SdkVersion).
-				WithTimeout(60 * time.Second),
-		),
+		Ipfs:     ipfs.NewIpfsAPI(ic),
 		// Zmq:    zmq.NewZmqAPI(c),
 		client: c,
+		market: c,
+		ipfs:   ic,
+		rpc:    rc,
+		info:   &infoCache{},
 	}
 }
 
@@ -92,30 +129,124 @@ func (s *Client) WithHeader(key, value string) *Client {
 	return s
 }
 
+// WithDefaultParams merges params into every index API request, e.g.
+// NewQuery().WithPrim().WithMeta(). Arguments set on a call take
+// precedence over defaults.
+func (s *Client) WithDefaultParams(params ...Query) *Client {
+	s.client.WithDefaultParams(params...)
+	return s
+}
+
+// WithUserAgent identifies the application in requests to all APIs, e.g.
+// "myapp/1.0 tzpro-go/v0.18.0". An empty agent restores the default
+// library version string.
 func (s *Client) WithUserAgent(agent string) *Client {
+	if agent == "" {
+		agent = "tzpro-go/v" + SdkVersion
+	}
 	s.client.WithUserAgent(agent)
+	s.market.WithUserAgent(agent)
+	s.ipfs.WithUserAgent(agent)
+	s.rpc.WithUserAgent(agent)
 	return s
 }
 
+// WithApiKey sets the API key for all endpoints including market and IPFS.
+// By default the key is read from the TZPRO_API_KEY environment variable.
 func (s *Client) WithApiKey(key string) *Client {
 	s.client.WithApiKey(key)
+	s.market.WithApiKey(key)
+	s.ipfs.WithApiKey(key)
+	s.rpc.WithApiKey(key)
 	return s
 }
 
 func (s *Client) WithMarketUrl(url string) *Client {
 	c := client.NewClient(url, nil).
-		WithApiKey(os.Getenv("TZPRO_API_KEY")).
-		WithUserAgent("tzpro-go/v" + SdkVersion)
+		WithApiKey(s.client.DefaultHeaders().Get("X-Api-Key")).
+		WithUserAgent(s.client.UserAgent())
 	s.Market = market.NewMarketAPI(c)
+	s.market = c
 	return s
 }
 
 func (s *Client) WithIpfsUrl(url string) *Client {
 	c := client.NewClient(url, nil).
-		WithApiKey(os.Getenv("TZPRO_API_KEY")).
-		WithUserAgent("tzpro-go/v" + SdkVersion).
+		WithApiKey(s.client.DefaultHeaders().Get("X-Api-Key")).
+		WithUserAgent(s.client.UserAgent()).
 		WithTimeout(60 * time.Second)
+	s.client.WithIpfs(c)
 	s.Ipfs = ipfs.NewIpfsAPI(c)
+	s.ipfs = c
+	return s
+}
+
+// WithRpcUrl sets the Tezos node RPC used for calls the API does not
+// provide such as Contract.RunView. Defaults to the TzPro RPC service.
+func (s *Client) WithRpcUrl(url string) *Client {
+	c := client.NewClient(url, nil).
+		WithApiKey(s.client.DefaultHeaders().Get("X-Api-Key")).
+		WithUserAgent(s.client.UserAgent())
+	s.client.WithRpc(c)
+	s.rpc = c
+	return s
+}
+
+// WithNetwork pins the client to the chain with id. The server's chain id
+// is verified on first use and requests fail when it does not match.
+func (s *Client) WithNetwork(id tezos.ChainIdHash) *Client {
+	s.client.WithNetwork(id)
+	return s
+}
+
+// WithEndpoints sets alternative API servers to fail over to when the
+// active server is unreachable or returns a server error.
+func (s *Client) WithEndpoints(urls ...string) *Client {
+	s.client.WithEndpoints(urls...)
+	return s
+}
+
+// Endpoint returns the API server currently in use.
+func (s Client) Endpoint() string {
+	return s.client.Endpoint()
+}
+
+// Do sends a raw request to an API endpoint not wrapped by the SDK and
+// decodes a JSON response into result.
+func (s *Client) Do(ctx context.Context, method, path string, query url.Values, body io.Reader, result any) error {
+	return s.client.Do(ctx, method, path, query, body, result)
+}
+
+// QuerySeries loads rows of the time-series endpoint at path into T.
+// Go methods cannot have type parameters, hence the client argument.
+func QuerySeries[T any](ctx context.Context, s *Client, path string, params SeriesParams) ([]*T, error) {
+	return client.QuerySeries[T](ctx, s.client, path, params)
+}
+
+// Successes returns all items of a batch result which did not fail
+// according to a *BatchError in err, e.g. index.BatchError keyed by
+// contract or defi.BatchError keyed by pool.
+func Successes[K comparable, T any](items map[K]T, err error) map[K]T {
+	return client.Successes(items, err)
+}
+
+// WithRecorder writes requests and responses of all APIs to dir. API keys
+// and other sensitive headers are not recorded.
+func (s *Client) WithRecorder(dir string) *Client {
+	s.client.WithRecorder(dir)
+	s.market.WithRecorder(dir)
+	s.ipfs.WithRecorder(dir)
+	s.rpc.WithRecorder(dir)
+	return s
+}
+
+// WithReplay serves all API responses from recordings in dir created by
+// WithRecorder instead of the network.
+func (s *Client) WithReplay(dir string) *Client {
+	s.client.WithReplay(dir)
+	s.market.WithReplay(dir)
+	s.ipfs.WithReplay(dir)
+	s.rpc.WithReplay(dir)
 	return s
 }
 
@@ -124,11 +255,46 @@ func (s *Client) WithTLS(tc *tls.Config) *Client {
 	return s
 }
 
+func (s *Client) WithCompression(enable bool) *Client {
+	s.client.WithCompression(enable)
+	return s
+}
+
 func (s *Client) WithTimeout(d time.Duration) *Client {
 	s.client.WithTimeout(d)
 	return s
 }
 
+// WithMaxConnsPerHost limits concurrent connections per API server
+// (default 10). It only applies to the default transport.
+func (s *Client) WithMaxConnsPerHost(n int) *Client {
+	s.client.WithMaxConnsPerHost(n)
+	s.market.WithMaxConnsPerHost(n)
+	s.ipfs.WithMaxConnsPerHost(n)
+	s.rpc.WithMaxConnsPerHost(n)
+	return s
+}
+
+// WithMaxIdleConns sets the number of idle connections kept for reuse per
+// API server (default 10). It only applies to the default transport.
+func (s *Client) WithMaxIdleConns(n int) *Client {
+	s.client.WithMaxIdleConns(n)
+	s.market.WithMaxIdleConns(n)
+	s.ipfs.WithMaxIdleConns(n)
+	s.rpc.WithMaxIdleConns(n)
+	return s
+}
+
+// WithIdleConnTimeout sets how long idle connections are kept open
+// (default 30s). It only applies to the default transport.
+func (s *Client) WithIdleConnTimeout(d time.Duration) *Client {
+	s.client.WithIdleConnTimeout(d)
+	s.market.WithIdleConnTimeout(d)
+	s.ipfs.WithIdleConnTimeout(d)
+	s.rpc.WithIdleConnTimeout(d)
+	return s
+}
+
 func (s *Client) WithRetry(num int, delay time.Duration) *Client {
 	s.client.WithRetry(num, delay)
 	return s
@@ -144,6 +310,47 @@ func (s *Client) WithCacheSize(sz int) *Client {
 	return s
 }
 
+// WithScriptCache resizes the contract script cache and sets a TTL after
+// which cached scripts are reloaded. Use a zero TTL to never expire.
+func (s *Client) WithScriptCache(sz int, ttl time.Duration) *Client {
+	s.client.WithScriptCache(sz, ttl)
+	return s
+}
+
+// WithKeepCode retains code and views in cached contract scripts which
+// are stripped by default to save memory.
+func (s *Client) WithKeepCode(keep bool) *Client {
+	s.client.WithKeepCode(keep)
+	return s
+}
+
+// PurgeScript removes a cached contract script forcing a reload on next use.
+func (s *Client) PurgeScript(addr Address) {
+	s.client.CacheRemove(addr)
+}
+
+// CacheStats returns script cache hit and miss counters and size.
+func (s *Client) CacheStats() CacheStats {
+	return s.client.CacheStats()
+}
+
+// Metrics returns request and cache counters summed across the index,
+// market, IPFS and node RPC clients.
+func (s *Client) Metrics() Metrics {
+	m := s.client.Metrics()
+	for _, c := range []*client.Client{s.market, s.ipfs, s.rpc} {
+		if c == nil || c == s.client {
+			continue
+		}
+		x := c.Metrics()
+		m.Requests += x.Requests
+		m.Retries += x.Retries
+		m.Errors += x.Errors
+		m.BytesReceived += x.BytesReceived
+	}
+	return m
+}
+
 func (s *Client) UseScriptCache(cache *lru.TwoQueueCache[Address, any]) {
 	s.client.UseScriptCache(cache)
 }
@@ -160,6 +367,12 @@ func (s Client) CacheGet(key Address) (any, bool) {
 	return s.client.CacheGet(key)
 }
 
+// CacheAdd stores val in the script cache. Contract scripts are
+// normalized like scripts loaded by the SDK.
 func (s Client) CacheAdd(key Address, val any) {
+	if script, ok := val.(*index.ContractScript); ok {
+		s.Contract.AddScript(key, script)
+		return
+	}
 	s.client.CacheAdd(key, val)
 }
@@ -0,0 +1,71 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzpro_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"blockwatch.cc/tzpro-go/tzpro"
+	"blockwatch.cc/tzpro-go/tzpro/tzprotest"
+)
+
+func TestGetStorageSeries(t *testing.T) {
+	m := tzprotest.NewMockTransport()
+	m.SetResponse("GET", "/explorer/contract/"+cacheTestAddr.String(), `{"storage_paid":1000}`)
+	m.SetResponse("GET", "/series/op", `[
+		[1704067200000, 100],
+		[1704153600000, 0],
+		[1704240000000, 50]
+	]`)
+	c := m.Client()
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		params tzpro.SeriesParams
+		want   []int64
+	}{
+		{"all", tzpro.NewSeriesParams(), []int64{950, 950, 1000}},
+		{"range", tzpro.NewSeriesParams().WithRange(day(1), day(2)), []int64{950, 950}},
+		{"limit", tzpro.SeriesParams{Limit: 1}, []int64{950}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := c.Contract.GetStorageSeries(context.Background(), cacheTestAddr, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(points) != len(tt.want) {
+				t.Fatalf("got %d points, want %d", len(points), len(tt.want))
+			}
+			for i, p := range points {
+				if p.StoragePaid != tt.want[i] {
+					t.Errorf("point %d: got %d, want %d", i, p.StoragePaid, tt.want[i])
+				}
+				if !p.Time.Equal(day(i + 1)) {
+					t.Errorf("point %d: got time %s, want %s", i, p.Time, day(i+1))
+				}
+			}
+		})
+	}
+
+	for _, r := range m.Requests() {
+		if strings.HasPrefix(r, "GET /series/op") && !strings.Contains(r, "receiver.eq="+cacheTestAddr.String()) {
+			t.Errorf("series request without receiver filter: %s", r)
+		}
+	}
+
+	unsupported := []tzpro.SeriesParams{
+		tzpro.NewSeriesParams().WithColumns("time"),
+		tzpro.NewSeriesParams().WithFill(tzpro.FillModeLinear),
+	}
+	for _, p := range unsupported {
+		if _, err := c.Contract.GetStorageSeries(context.Background(), cacheTestAddr, p); err == nil {
+			t.Errorf("expected error for params %+v", p)
+		}
+	}
+}
@@ -16,6 +16,7 @@ type TokenAPI interface {
 	GetLedger(context.Context, Address) (*Ledger, error)
 
 	GetTokenMetadata(context.Context, TokenAddress) (*TokenMetadata, error)
+	ResolveTokenMetadata(context.Context, Address, int64) (*TokenMetadataContent, error)
 	GetLedgerMetadata(context.Context, Address) (*TokenMetadata, error)
 
 	ListLedgerTokens(context.Context, Address, Query) ([]*Token, error)
@@ -24,6 +25,7 @@ type TokenAPI interface {
 
 	ListTokenEvents(context.Context, TokenAddress, Query) ([]*TokenEvent, error)
 	ListTokenBalances(context.Context, TokenAddress, Query) ([]*TokenBalance, error)
+	GetTokenBalance(context.Context, TokenAddress, Address) (*TokenBalance, error)
 
 	// firehose
 	ListTokens(context.Context, Query) ([]*Token, error)
@@ -64,10 +66,18 @@ type Token struct {
 	McapUSD        float64   `json:"mcap_usd,string"`
 }
 
+func (t *Token) GetRowId() uint64 {
+	return t.Id
+}
+
 func (t Token) Address() TokenAddress {
 	return NewTokenAddress(t.Contract, t.TokenId)
 }
 
+// TokenList pages token listings, e.g. TokenList(list).Cursor() returns
+// the cursor for the next page of ListTokens or ListLedgerTokens.
+type TokenList = client.List[*Token]
+
 func (c *tokenClient) GetToken(ctx context.Context, addr TokenAddress) (*Token, error) {
 	t := &Token{}
 	u := fmt.Sprintf("/v1/tokens/%s", addr)
@@ -78,10 +88,10 @@ func (c *tokenClient) GetToken(ctx context.Context, addr TokenAddress) (*Token,
 }
 
 func (c *tokenClient) ListTokens(ctx context.Context, params Query) ([]*Token, error) {
-	list := make([]*Token, 0)
-	u := params.WithPath("/v1/tokens").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	list := make(TokenList, 0)
+	q := params.WithPath("/v1/tokens")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
-	return list, nil
+	return []*Token(list), nil
 }
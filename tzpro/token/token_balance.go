@@ -6,6 +6,9 @@ package token
 import (
 	"context"
 	"fmt"
+
+	"blockwatch.cc/tzgo/tezos"
+	"blockwatch.cc/tzpro-go/internal/client"
 )
 
 type TokenBalance struct {
@@ -32,8 +35,8 @@ type TokenBalance struct {
 
 func (c *tokenClient) ListLedgerBalances(ctx context.Context, addr Address, params Query) ([]*TokenBalance, error) {
 	list := make([]*TokenBalance, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/ledgers/%s/balances", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/ledgers/%s/balances", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -41,9 +44,41 @@ func (c *tokenClient) ListLedgerBalances(ctx context.Context, addr Address, para
 
 func (c *tokenClient) ListTokenBalances(ctx context.Context, addr TokenAddress, params Query) ([]*TokenBalance, error) {
 	list := make([]*TokenBalance, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/tokens/%s/balances", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/tokens/%s/balances", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
+
+// GetTokenBalance returns the balance of a single holder. When the holder
+// has never owned the token a zero balance is returned instead of an error.
+func (c *tokenClient) GetTokenBalance(ctx context.Context, addr TokenAddress, owner Address) (*TokenBalance, error) {
+	params := client.NewQuery().AndEqual("owner", owner).WithLimit(1)
+	list, err := c.ListTokenBalances(ctx, addr, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) > 0 {
+		return list[0], nil
+	}
+	tok, err := c.GetToken(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenBalance{
+		Owner:    owner,
+		Contract: tok.Contract,
+		TokenId:  tok.TokenId,
+		Kind:     tok.Kind,
+		Type:     tok.Type,
+		Name:     tok.Name,
+		Symbol:   tok.Symbol,
+		Decimals: tok.Decimals,
+		Balance:  tezos.Zero,
+		VolSent:  tezos.Zero,
+		VolRecv:  tezos.Zero,
+		VolMint:  tezos.Zero,
+		VolBurn:  tezos.Zero,
+	}, nil
+}
@@ -31,8 +31,8 @@ type TokenEvent struct {
 
 func (c *tokenClient) ListEvents(ctx context.Context, params Query) ([]*TokenEvent, error) {
 	list := make([]*TokenEvent, 0)
-	u := params.WithPath("/v1/ledgers/events").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/ledgers/events")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -40,8 +40,8 @@ func (c *tokenClient) ListEvents(ctx context.Context, params Query) ([]*TokenEve
 
 func (c *tokenClient) ListLedgerEvents(ctx context.Context, addr Address, params Query) ([]*TokenEvent, error) {
 	list := make([]*TokenEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/ledgers/%s/events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/ledgers/%s/events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -49,8 +49,8 @@ func (c *tokenClient) ListLedgerEvents(ctx context.Context, addr Address, params
 
 func (c *tokenClient) ListTokenEvents(ctx context.Context, addr TokenAddress, params Query) ([]*TokenEvent, error) {
 	list := make([]*TokenEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/tokens/%s/events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/tokens/%s/events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -40,18 +40,18 @@ func (c *tokenClient) GetLedger(ctx context.Context, addr Address) (*Ledger, err
 
 func (c *tokenClient) ListLedgers(ctx context.Context, params Query) ([]*Ledger, error) {
 	list := make([]*Ledger, 0)
-	u := params.WithPath("/v1/ledgers").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/ledgers")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
 }
 
 func (c *tokenClient) ListLedgerTokens(ctx context.Context, addr Address, params Query) ([]*Token, error) {
-	list := make([]*Token, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/ledgers/%s/tokens", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	list := make(TokenList, 0)
+	q := params.WithPath(fmt.Sprintf("/v1/ledgers/%s/tokens", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
-	return list, nil
+	return []*Token(list), nil
 }
@@ -4,9 +4,14 @@
 package token
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"blockwatch.cc/tzgo/micheline"
 )
 
 type TokenMetadata struct {
@@ -21,10 +26,52 @@ type TokenMetadata struct {
 	Data        json.RawMessage `json:"data"`
 }
 
+// TokenMetadataContent contains common TZIP-12/21 fields decoded from
+// on-chain and off-chain token metadata. Raw holds the full metadata.
+type TokenMetadataContent struct {
+	Name     string
+	Symbol   string
+	Decimals int
+	Raw      map[string]any
+}
+
+// Content decodes name, symbol and decimals from metadata. Decimals
+// may be encoded as number or string.
+func (m TokenMetadata) Content() (*TokenMetadataContent, error) {
+	c := &TokenMetadataContent{
+		Raw: make(map[string]any),
+	}
+	if len(m.Data) == 0 || string(m.Data) == "null" {
+		return c, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(m.Data))
+	dec.UseNumber()
+	if err := dec.Decode(&c.Raw); err != nil {
+		return nil, fmt.Errorf("decoding token metadata: %w", err)
+	}
+	c.Name, _ = c.Raw["name"].(string)
+	c.Symbol, _ = c.Raw["symbol"].(string)
+	switch v := c.Raw["decimals"].(type) {
+	case json.Number:
+		d, err := strconv.Atoi(v.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid token decimals %q", v)
+		}
+		c.Decimals = d
+	case string:
+		d, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token decimals %q", v)
+		}
+		c.Decimals = d
+	}
+	return c, nil
+}
+
 func (c *tokenClient) ListMetadata(ctx context.Context, params Query) ([]*TokenMetadata, error) {
 	list := make([]*TokenMetadata, 0)
-	u := params.WithPath("/v1/meta").Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath("/v1/meta")
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -47,3 +94,74 @@ func (c *tokenClient) GetTokenMetadata(ctx context.Context, addr TokenAddress) (
 	}
 	return val, nil
 }
+
+// ResolveTokenMetadata reads TZIP-12 metadata of token tokenId from the
+// token_metadata bigmap of FA2 contract addr. When the token info holds a
+// TZIP-21 URI under the empty key, off-chain JSON is loaded from IPFS and
+// merged, with on-chain fields taking precedence. Only ipfs:// URIs are
+// loaded, other URIs stay in Raw under the empty key.
+func (c *tokenClient) ResolveTokenMetadata(ctx context.Context, addr Address, tokenId int64) (*TokenMetadataContent, error) {
+	var contract struct {
+		Bigmaps map[string]int64 `json:"bigmaps"`
+	}
+	if err := c.client.Get(ctx, fmt.Sprintf("/explorer/contract/%s", addr), nil, &contract); err != nil {
+		return nil, err
+	}
+	id, ok := contract.Bigmaps["token_metadata"]
+	if !ok {
+		return nil, fmt.Errorf("%s: no token_metadata bigmap", addr)
+	}
+	var val struct {
+		ValuePrim *Prim `json:"value_prim"`
+	}
+	q := NewQuery().WithPrim().WithPath(fmt.Sprintf("/explorer/bigmap/%d/%d", id, tokenId))
+	if err := c.client.GetQuery(ctx, q, nil, &val); err != nil {
+		return nil, err
+	}
+	if val.ValuePrim == nil {
+		return nil, fmt.Errorf("%s: missing token_metadata value for token %d", addr, tokenId)
+	}
+	info, ok := tokenInfo(*val.ValuePrim)
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid token_metadata value for token %d", addr, tokenId)
+	}
+
+	raw := make(map[string]any)
+	if uri := string(info[""]); strings.HasPrefix(uri, "ipfs://") {
+		files := c.client.Ipfs()
+		if files == nil {
+			return nil, fmt.Errorf("loading token metadata from %s: no IPFS client", uri)
+		}
+		if err := files.Get(ctx, "/ipfs/"+strings.TrimPrefix(uri, "ipfs://"), nil, &raw); err != nil {
+			return nil, fmt.Errorf("loading token metadata from %s: %w", uri, err)
+		}
+	}
+	for k, v := range info {
+		raw[k] = string(v)
+	}
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	return TokenMetadata{
+		Contract: addr,
+		TokenId:  NewZ(tokenId),
+		Data:     buf,
+	}.Content()
+}
+
+// tokenInfo extracts the token_info map from a TZIP-12 token_metadata
+// value of type pair (nat %token_id) (map %token_info string bytes).
+func tokenInfo(p Prim) (map[string][]byte, bool) {
+	if p.OpCode != micheline.D_PAIR || len(p.Args) != 2 || !p.Args[1].IsSequence() {
+		return nil, false
+	}
+	info := make(map[string][]byte, len(p.Args[1].Args))
+	for _, elt := range p.Args[1].Args {
+		if elt.OpCode != micheline.D_ELT || len(elt.Args) != 2 {
+			return nil, false
+		}
+		info[elt.Args[0].String] = elt.Args[1].Bytes
+	}
+	return info, true
+}
@@ -4,12 +4,14 @@
 package token
 
 import (
+	"blockwatch.cc/tzgo/micheline"
 	"blockwatch.cc/tzgo/tezos"
 	"blockwatch.cc/tzpro-go/internal/client"
 )
 
 type (
 	Query = client.Query
+	Prim  = micheline.Prim
 
 	OpHash       = tezos.OpHash
 	Address      = tezos.Address
@@ -18,6 +20,8 @@ type (
 )
 
 var (
+	NewQuery        = client.NewQuery
 	ParseAddress    = tezos.ParseAddress
 	NewTokenAddress = tezos.NewToken
+	NewZ            = tezos.NewZ
 )
@@ -0,0 +1,36 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzpro_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"blockwatch.cc/tzpro-go/tzpro/tzprotest"
+)
+
+func TestResolveTokenMetadata(t *testing.T) {
+	const uri = "ipfs://QmTestTokenMetadata"
+	m := tzprotest.NewMockTransport()
+	m.SetResponse("GET", "/explorer/contract/"+cacheTestAddr.String(), `{"bigmaps":{"ledger":1,"token_metadata":7}}`)
+	m.SetResponse("GET", "/explorer/bigmap/7/3", fmt.Sprintf(`{"value_prim":{"prim":"Pair","args":[{"int":"3"},[
+		{"prim":"Elt","args":[{"string":""},{"bytes":%q}]},
+		{"prim":"Elt","args":[{"string":"symbol"},{"bytes":%q}]}
+	]]}}`, hex.EncodeToString([]byte(uri)), hex.EncodeToString([]byte("TST"))))
+	m.SetResponse("GET", "/ipfs/QmTestTokenMetadata", `{"name":"Test","symbol":"OFF","decimals":"6"}`)
+
+	c := m.Client()
+	meta, err := c.Token.ResolveTokenMetadata(context.Background(), cacheTestAddr, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Name != "Test" || meta.Decimals != 6 {
+		t.Errorf("off-chain fields not merged: %+v", meta)
+	}
+	if meta.Symbol != "TST" {
+		t.Errorf("got symbol %q, want on-chain TST", meta.Symbol)
+	}
+}
@@ -21,6 +21,7 @@ type (
 	Z           = tezos.Z
 
 	Query          = client.Query
+	SeriesParams   = client.SeriesParams
 	FilterMode     = client.FilterMode
 	FillMode       = client.FillMode
 	OrderType      = client.OrderType
@@ -28,30 +29,46 @@ type (
 	ErrApi         = client.ErrApi
 	ErrHttp        = client.ErrHttp
 	ErrRateLimited = client.ErrRateLimited
+	CacheStats     = client.CacheStats
+	BatchError     = client.BatchError[Address]
+	Metrics        = client.Metrics
+	Checkpoint     = client.Checkpoint
+	CheckpointFunc = client.CheckpointFunc
 )
 
 var (
-	NewAddress       = tezos.MustParseAddress
-	ParseAddress     = tezos.ParseAddress
-	NewPoolAddres    = defi.MustParsePoolAddress
-	ParsePoolAddress = defi.ParsePoolAddress
-	NewToken         = tezos.MustParseToken
-	NewQuery         = client.NewQuery
-	IsErrApi         = client.IsErrApi
-	IsErrHttp        = client.IsErrHttp
-	IsErrRateLimited = client.IsErrRateLimited
-	ErrorStatus      = client.ErrorStatus
+	NewAddress        = tezos.MustParseAddress
+	ParseAddress      = tezos.ParseAddress
+	NewPoolAddres     = defi.MustParsePoolAddress
+	ParsePoolAddress  = defi.ParsePoolAddress
+	NewToken          = tezos.MustParseToken
+	NewQuery          = client.NewQuery
+	NewSeriesParams   = client.NewSeriesParams
+	NewCheckpoint     = client.NewCheckpoint
+	IsErrApi          = client.IsErrApi
+	IsErrHttp         = client.IsErrHttp
+	IsErrRateLimited  = client.IsErrRateLimited
+	IsBatchError      = client.IsBatchError[Address]
+	ErrorStatus       = client.ErrorStatus
+	WithRequestId     = client.WithRequestId
+	RequestId         = client.RequestId
+	ErrInvalidAddress = client.ErrInvalidAddress
+	ErrNoData         = client.ErrNoData
+	IsNotFound        = client.IsNotFound
+
+	DecodeContractStream = index.DecodeContractStream
+	DecodeCallStream     = index.DecodeCallStream
 
 	NoQuery = NewQuery()
 )
 
 const (
-	FillModeInvalid FillMode = ""
-	FillModeNone    FillMode = "none"
-	FillModeNull    FillMode = "null"
-	FillModeLast    FillMode = "last"
-	FillModeLinear  FillMode = "linear"
-	FillModeZero    FillMode = "zero"
+	FillModeInvalid = client.FillModeInvalid
+	FillModeNone    = client.FillModeNone
+	FillModeNull    = client.FillModeNull
+	FillModeLast    = client.FillModeLast
+	FillModeLinear  = client.FillModeLinear
+	FillModeZero    = client.FillModeZero
 )
 
 const (
@@ -80,8 +97,9 @@ const (
 )
 
 const (
-	FormatJSON FormatType = "json"
-	FormatCSV  FormatType = "csv"
+	FormatJSON    = client.FormatJSON
+	FormatCSV     = client.FormatCSV
+	FormatMsgpack = client.FormatMsgpack
 )
 
 const (
@@ -165,6 +183,10 @@ func Range(key string, from, to any) Query {
 	return NewQuery().AndRange(key, from, to)
 }
 
+func TimeRange(key string, from, to time.Time) Query {
+	return NewQuery().AndTimeRange(key, from, to)
+}
+
 func Regexp(key string, re string) Query {
 	return NewQuery().AndRegexp(key, re)
 }
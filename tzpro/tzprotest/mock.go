@@ -0,0 +1,214 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+// Package tzprotest provides in-memory mocks of the tzpro API for unit
+// tests. MockClient implements tzpro.API with canned results per method,
+// MockTransport serves canned HTTP replies to a regular *tzpro.Client.
+package tzprotest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"blockwatch.cc/tzpro-go/tzpro"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// Names of mocked API methods for use with SetResponse, SetError and Calls.
+const (
+	MethodPing           = "Ping"
+	MethodServerInfo     = "ServerInfo"
+	MethodDo             = "Do"
+	MethodEndpoint       = "Endpoint"
+	MethodMetrics        = "Metrics"
+	MethodCacheStats     = "CacheStats"
+	MethodCacheGet       = "CacheGet"
+	MethodCacheAdd       = "CacheAdd"
+	MethodPurgeScript    = "PurgeScript"
+	MethodUseScriptCache = "UseScriptCache"
+	MethodRetries        = "Retries"
+	MethodRetryDelay     = "RetryDelay"
+)
+
+// MockClient implements tzpro.API with results set per method. Methods
+// without a result return zero values, Endpoint returns MockServer. The
+// script cache is a plain in-memory map unless a result is set for
+// CacheGet. MockClient is safe for concurrent use.
+type MockClient struct {
+	mu        sync.Mutex
+	responses map[string]any
+	errors    map[string]error
+	calls     map[string]int
+	cache     map[tzpro.Address]any
+}
+
+var _ tzpro.API = (*MockClient)(nil)
+
+func NewMockClient() *MockClient {
+	return &MockClient{
+		responses: make(map[string]any),
+		errors:    make(map[string]error),
+		calls:     make(map[string]int),
+		cache:     make(map[tzpro.Address]any),
+	}
+}
+
+// SetResponse sets the result returned by method. The value must have the
+// method's result type, e.g. *tzpro.ServerInfo for ServerInfo. For Do
+// the value is JSON encoded and decoded into the caller's result, byte
+// slices and strings are used as JSON text as is.
+func (m *MockClient) SetResponse(method string, v any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[method] = v
+}
+
+// SetError makes method fail with err. Only Ping, ServerInfo and Do
+// return errors. A nil err removes the error.
+func (m *MockClient) SetError(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.errors, method)
+		return
+	}
+	m.errors[method] = err
+}
+
+// Calls returns how often method was called.
+func (m *MockClient) Calls(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+// Reset removes all results, errors, recorded calls and cache entries.
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = make(map[string]any)
+	m.errors = make(map[string]error)
+	m.calls = make(map[string]int)
+	m.cache = make(map[tzpro.Address]any)
+}
+
+// call records a call to method and returns its result and error.
+func (m *MockClient) call(method string) (any, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[method]++
+	return m.responses[method], m.errors[method]
+}
+
+func (m *MockClient) Ping(context.Context) error {
+	_, err := m.call(MethodPing)
+	return err
+}
+
+func (m *MockClient) ServerInfo(context.Context) (*tzpro.ServerInfo, error) {
+	v, err := m.call(MethodServerInfo)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return &tzpro.ServerInfo{}, nil
+	}
+	return v.(*tzpro.ServerInfo), nil
+}
+
+func (m *MockClient) Do(_ context.Context, _, _ string, _ url.Values, body io.Reader, result any) error {
+	v, err := m.call(MethodDo)
+	if body != nil {
+		_, _ = io.Copy(io.Discard, body)
+	}
+	if err != nil || v == nil || result == nil {
+		return err
+	}
+	var buf []byte
+	switch val := v.(type) {
+	case []byte:
+		buf = val
+	case string:
+		buf = []byte(val)
+	default:
+		buf, err = json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("tzprotest: encoding Do response: %w", err)
+		}
+	}
+	return json.Unmarshal(buf, result)
+}
+
+func (m *MockClient) Endpoint() string {
+	v, _ := m.call(MethodEndpoint)
+	if v == nil {
+		return MockServer
+	}
+	return v.(string)
+}
+
+func (m *MockClient) Metrics() tzpro.Metrics {
+	v, _ := m.call(MethodMetrics)
+	if v == nil {
+		return tzpro.Metrics{}
+	}
+	return v.(tzpro.Metrics)
+}
+
+func (m *MockClient) CacheStats() tzpro.CacheStats {
+	v, _ := m.call(MethodCacheStats)
+	if v == nil {
+		return tzpro.CacheStats{}
+	}
+	return v.(tzpro.CacheStats)
+}
+
+func (m *MockClient) CacheGet(key tzpro.Address) (any, bool) {
+	v, _ := m.call(MethodCacheGet)
+	if v != nil {
+		return v, true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	val, ok := m.cache[key]
+	return val, ok
+}
+
+func (m *MockClient) CacheAdd(key tzpro.Address, val any) {
+	m.call(MethodCacheAdd)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache[key] = val
+}
+
+func (m *MockClient) PurgeScript(addr tzpro.Address) {
+	m.call(MethodPurgeScript)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cache, addr)
+}
+
+func (m *MockClient) UseScriptCache(*lru.TwoQueueCache[tzpro.Address, any]) {
+	m.call(MethodUseScriptCache)
+}
+
+func (m *MockClient) Retries() int {
+	v, _ := m.call(MethodRetries)
+	if v == nil {
+		return 0
+	}
+	return v.(int)
+}
+
+func (m *MockClient) RetryDelay() time.Duration {
+	v, _ := m.call(MethodRetryDelay)
+	if v == nil {
+		return 0
+	}
+	return v.(time.Duration)
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzprotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"blockwatch.cc/tzpro-go/tzpro"
+)
+
+func TestMockClient(t *testing.T) {
+	var api tzpro.API = NewMockClient()
+	m := api.(*MockClient)
+	ctx := context.Background()
+
+	m.SetResponse(MethodServerInfo, &tzpro.ServerInfo{Network: "Mainnet", Height: 42})
+	info, err := api.ServerInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Network != "Mainnet" || info.Height != 42 {
+		t.Errorf("unexpected server info %+v", info)
+	}
+
+	errDown := errors.New("down")
+	m.SetError(MethodPing, errDown)
+	if err := api.Ping(ctx); !errors.Is(err, errDown) {
+		t.Errorf("got error %v, want %v", err, errDown)
+	}
+	m.SetError(MethodPing, nil)
+	if err := api.Ping(ctx); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if n := m.Calls(MethodPing); n != 2 {
+		t.Errorf("got %d ping calls, want 2", n)
+	}
+
+	m.SetResponse(MethodDo, `{"height":7}`)
+	var res struct {
+		Height int64 `json:"height"`
+	}
+	if err := api.Do(ctx, "GET", "/explorer/tip", nil, nil, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Height != 7 {
+		t.Errorf("got height %d, want 7", res.Height)
+	}
+
+	addr := tzpro.NewAddress("KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5")
+	api.CacheAdd(addr, 1)
+	if v, ok := api.CacheGet(addr); !ok || v != 1 {
+		t.Errorf("cache miss after add: %v %t", v, ok)
+	}
+	api.PurgeScript(addr)
+	if _, ok := api.CacheGet(addr); ok {
+		t.Error("cache hit after purge")
+	}
+	if got := api.Endpoint(); got != MockServer {
+		t.Errorf("got endpoint %q, want %q", got, MockServer)
+	}
+}
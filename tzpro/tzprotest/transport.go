@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzprotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"blockwatch.cc/tzpro-go/tzpro"
+)
+
+// MockServer is the base URL used by clients of MockTransport.
+const MockServer = "http://tzpro.mock"
+
+// Response is a canned reply for a single API endpoint. When Err is set
+// the request fails with a transport error, otherwise Body is returned
+// with Status.
+type Response struct {
+	Status int
+	Body   []byte
+	Err    error
+}
+
+// MockTransport serves canned responses from memory keyed by HTTP method
+// and URL path. It implements http.RoundTripper so that a regular
+// *tzpro.Client created by Client sends all requests to the mock, which
+// keeps the full decoding logic of the SDK under test. Unknown endpoints
+// reply with 404 Not Found. MockTransport is safe for concurrent use.
+type MockTransport struct {
+	mu        sync.Mutex
+	responses map[string]Response
+	requests  []string
+}
+
+func NewMockTransport() *MockTransport {
+	return &MockTransport{
+		responses: make(map[string]Response),
+	}
+}
+
+// Client returns a tzpro client which sends all requests including
+// market and IPFS requests to m.
+func (m *MockTransport) Client() *tzpro.Client {
+	return tzpro.NewClient(MockServer, &http.Client{Transport: m}).WithApiKey("")
+}
+
+// SetResponse replies to method and path with v encoded as JSON. Byte
+// slices and strings are returned as is.
+func (m *MockTransport) SetResponse(method, path string, v any) error {
+	var body []byte
+	switch val := v.(type) {
+	case []byte:
+		body = val
+	case string:
+		body = []byte(val)
+	default:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		body = buf
+	}
+	m.set(method, path, Response{Status: http.StatusOK, Body: body})
+	return nil
+}
+
+// SetError fails requests to method and path. A non-zero status replies
+// with an API error of this status, otherwise err is returned as
+// transport error.
+func (m *MockTransport) SetError(method, path string, status int, err error) {
+	if status == 0 {
+		m.set(method, path, Response{Err: err})
+		return
+	}
+	msg := http.StatusText(status)
+	if err != nil {
+		msg = err.Error()
+	}
+	body, _ := json.Marshal(tzpro.ErrApi{Status_: status, Message: msg})
+	m.set(method, path, Response{Status: status, Body: body})
+}
+
+// Reset removes all responses and recorded requests.
+func (m *MockTransport) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = make(map[string]Response)
+	m.requests = m.requests[:0]
+}
+
+// Requests returns all requests served so far as "METHOD /path?query".
+func (m *MockTransport) Requests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.requests...)
+}
+
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	m.mu.Lock()
+	m.requests = append(m.requests, req.Method+" "+req.URL.RequestURI())
+	r, ok := m.responses[key(req.Method, req.URL.Path)]
+	m.mu.Unlock()
+	if !ok {
+		r = Response{
+			Status: http.StatusNotFound,
+			Body:   []byte(fmt.Sprintf(`{"status":404,"message":"no mock response for %s %s"}`, req.Method, req.URL.Path)),
+		}
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (m *MockTransport) set(method, path string, r Response) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[key(method, path)] = r
+}
+
+func key(method, path string) string {
+	return strings.ToUpper(method) + " /" + strings.TrimLeft(path, "/")
+}
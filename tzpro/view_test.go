@@ -0,0 +1,135 @@
+// Copyright (c) 2024 Blockwatch Data Inc.
+// Author: alex@blockwatch.cc
+
+package tzpro_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blockwatch.cc/tzgo/micheline"
+	"blockwatch.cc/tzpro-go/tzpro"
+	"blockwatch.cc/tzpro-go/tzpro/index"
+	"blockwatch.cc/tzpro-go/tzpro/tzprotest"
+)
+
+const runViewPath = "/chains/main/blocks/head/helpers/scripts/run_script_view"
+
+func TestRunView(t *testing.T) {
+	m := tzprotest.NewMockTransport()
+	if err := m.SetResponse("GET", "/explorer/contract/"+cacheTestAddr.String()+"/script", newCacheTestScript()); err != nil {
+		t.Fatal(err)
+	}
+	m.SetResponse("GET", "/explorer/tip", `{"chain_id":"NetXdQprcVkpaWU"}`)
+	m.SetResponse("POST", runViewPath, `{"data":{"int":"42"}}`)
+
+	c := m.Client()
+	val, err := c.Contract.RunView(context.Background(), cacheTestAddr, "total", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(val.Value); got != "42" {
+		t.Errorf("got value %q, want 42", got)
+	}
+	if val.Prim == nil || val.Prim.Int.Int64() != 42 {
+		t.Errorf("unexpected prim %v", val.Prim)
+	}
+
+	_, err = c.Contract.RunView(context.Background(), cacheTestAddr, "missing", nil)
+	if err == nil {
+		t.Fatal("expected error for unknown view")
+	}
+	for _, r := range m.Requests() {
+		if r == "POST "+runViewPath {
+			return
+		}
+	}
+	t.Errorf("view was not run on the node, requests: %v", m.Requests())
+}
+
+const offchainTestMeta = `{
+	"address": "KT1TxqZ8QtKvLu3V3JH7Gx58n7Co8pgtpQU5",
+	"tz16": {
+		"views": [{
+			"name": "add",
+			"implementations": [
+				{"restApiQuery": {"specificationUri": "https://example.com/api.json", "path": "/add"}},
+				{"michelsonStorageView": {
+					"parameter": {"prim": "nat"},
+					"returnType": {"prim": "nat"},
+					"code": [{"prim": "UNPAIR"}, {"prim": "ADD"}]
+				}}
+			]
+		}, {
+			"name": "rest_only",
+			"implementations": [
+				{"restApiQuery": {"specificationUri": "https://example.com/api.json", "path": "/x"}}
+			]
+		}]
+	}
+}`
+
+func TestRunOffchainView(t *testing.T) {
+	var meta index.Metadata
+	if err := json.Unmarshal([]byte(offchainTestMeta), &meta); err != nil {
+		t.Fatal(err)
+	}
+	script := newCacheTestScript()
+	views, err := script.OffchainViews(&meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 1 || views[0].Name != "add" {
+		t.Fatalf("unexpected views %v", views)
+	}
+	if views, _ := script.OffchainViews(nil); len(views) != 0 {
+		t.Fatalf("got views without metadata: %v", views)
+	}
+
+	var body struct {
+		Script  micheline.Code `json:"script"`
+		Input   micheline.Prim `json:"input"`
+		ChainId string         `json:"chain_id"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/explorer/contract/" + cacheTestAddr.String() + "/script":
+			json.NewEncoder(w).Encode(newCacheTestScript())
+		case "/explorer/contract/" + cacheTestAddr.String() + "/storage":
+			w.Write([]byte(`{"prim":{"int":"40"}}`))
+		case "/explorer/tip":
+			w.Write([]byte(`{"chain_id":"NetXdQprcVkpaWU"}`))
+		case "/chains/main/blocks/head/helpers/scripts/run_code":
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding run_code request: %v", err)
+			}
+			w.Write([]byte(`{"storage":{"prim":"Some","args":[{"int":"42"}]},"operations":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := tzpro.NewClient(srv.URL, nil).WithRpcUrl(srv.URL).WithApiKey("")
+	val, err := c.Contract.RunOffchainView(context.Background(), cacheTestAddr, views[0], map[string]any{"": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(val.Value); got != "42" {
+		t.Errorf("got value %q, want 42", got)
+	}
+	if body.ChainId != "NetXdQprcVkpaWU" {
+		t.Errorf("unexpected chain id %q", body.ChainId)
+	}
+	want := micheline.NewPairType(micheline.NewPrim(micheline.T_NAT), micheline.NewPrim(micheline.T_NAT))
+	if got := body.Script.Param.Args[0]; !got.IsEqual(want) {
+		t.Errorf("parameter type %s, want %s", got.Dump(), want.Dump())
+	}
+	if body.Input.OpCode != micheline.D_PAIR || body.Input.Args[0].Int.Int64() != 2 || body.Input.Args[1].Int.Int64() != 40 {
+		t.Errorf("unexpected input %s", body.Input.Dump())
+	}
+}
@@ -10,8 +10,8 @@ import (
 
 func (c *walletClient) ListDexEvents(ctx context.Context, addr Address, params Query) ([]*DexEvent, error) {
 	list := make([]*DexEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/dex_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/dex_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -19,8 +19,8 @@ func (c *walletClient) ListDexEvents(ctx context.Context, addr Address, params Q
 
 func (c *walletClient) ListDexPositions(ctx context.Context, addr Address, params Query) ([]*DexPosition, error) {
 	list := make([]*DexPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/dex_positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/dex_positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -28,8 +28,8 @@ func (c *walletClient) ListDexPositions(ctx context.Context, addr Address, param
 
 func (c *walletClient) ListDexTrades(ctx context.Context, addr Address, params Query) ([]*DexTrade, error) {
 	list := make([]*DexTrade, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/dex_trades", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/dex_trades", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -37,8 +37,8 @@ func (c *walletClient) ListDexTrades(ctx context.Context, addr Address, params Q
 
 func (c *walletClient) ListFarmEvents(ctx context.Context, addr Address, params Query) ([]*FarmEvent, error) {
 	list := make([]*FarmEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/farm_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/farm_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -46,8 +46,8 @@ func (c *walletClient) ListFarmEvents(ctx context.Context, addr Address, params
 
 func (c *walletClient) ListFarmPositions(ctx context.Context, addr Address, params Query) ([]*FarmPosition, error) {
 	list := make([]*FarmPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/farm_positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/farm_positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -55,8 +55,8 @@ func (c *walletClient) ListFarmPositions(ctx context.Context, addr Address, para
 
 func (c *walletClient) ListLendingEvents(ctx context.Context, addr Address, params Query) ([]*LendingEvent, error) {
 	list := make([]*LendingEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/lend_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/lend_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -64,8 +64,8 @@ func (c *walletClient) ListLendingEvents(ctx context.Context, addr Address, para
 
 func (c *walletClient) ListLendingPositions(ctx context.Context, addr Address, params Query) ([]*LendingPosition, error) {
 	list := make([]*LendingPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/lend_positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/lend_positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
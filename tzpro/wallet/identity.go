@@ -10,8 +10,8 @@ import (
 
 func (c *walletClient) ListDomains(ctx context.Context, addr Address, params Query) ([]*Domain, error) {
 	list := make([]*Domain, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/domains", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/domains", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -19,8 +19,8 @@ func (c *walletClient) ListDomains(ctx context.Context, addr Address, params Que
 
 func (c *walletClient) ListDomainEvents(ctx context.Context, addr Address, params Query) ([]*DomainEvent, error) {
 	list := make([]*DomainEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/domain_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/domain_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -37,8 +37,8 @@ func (c *walletClient) GetProfile(ctx context.Context, addr Address) (*Profile,
 
 func (c *walletClient) ListProfileEvents(ctx context.Context, addr Address, params Query) ([]*ProfileEvent, error) {
 	list := make([]*ProfileEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/profile_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/profile_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -46,8 +46,8 @@ func (c *walletClient) ListProfileEvents(ctx context.Context, addr Address, para
 
 func (c *walletClient) ListProfileClaims(ctx context.Context, addr Address, params Query) ([]*ProfileClaim, error) {
 	list := make([]*ProfileClaim, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/profile_claims", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/profile_claims", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -10,8 +10,8 @@ import (
 
 func (c *walletClient) ListNftEvents(ctx context.Context, addr Address, params Query) ([]*NftEvent, error) {
 	list := make([]*NftEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/nft_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/nft_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -19,8 +19,8 @@ func (c *walletClient) ListNftEvents(ctx context.Context, addr Address, params Q
 
 func (c *walletClient) ListNftPositions(ctx context.Context, addr Address, params Query) ([]*NftPosition, error) {
 	list := make([]*NftPosition, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/nft_positions", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/nft_positions", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -28,8 +28,8 @@ func (c *walletClient) ListNftPositions(ctx context.Context, addr Address, param
 
 func (c *walletClient) ListNftTrades(ctx context.Context, addr Address, params Query) ([]*NftTrade, error) {
 	list := make([]*NftTrade, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/nft_trades", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/nft_trades", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -10,8 +10,8 @@ import (
 
 func (c *walletClient) ListTokenBalances(ctx context.Context, addr Address, params Query) ([]*TokenBalance, error) {
 	list := make([]*TokenBalance, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/balances", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/balances", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil
@@ -19,8 +19,8 @@ func (c *walletClient) ListTokenBalances(ctx context.Context, addr Address, para
 
 func (c *walletClient) ListTokenEvents(ctx context.Context, addr Address, params Query) ([]*TokenEvent, error) {
 	list := make([]*TokenEvent, 0)
-	u := params.WithPath(fmt.Sprintf("/v1/wallets/%s/token_events", addr)).Url()
-	if err := c.client.Get(ctx, u, nil, &list); err != nil {
+	q := params.WithPath(fmt.Sprintf("/v1/wallets/%s/token_events", addr))
+	if err := c.client.GetQuery(ctx, q, nil, &list); err != nil {
 		return nil, err
 	}
 	return list, nil